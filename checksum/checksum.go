@@ -0,0 +1,158 @@
+// Package checksum computes file digests and reads and writes SHA256SUMS style manifests,
+// the format of sha256sum(1): one "<hex digest>  <file name>" line per file.
+package checksum
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+type Algorithm string
+
+const (
+	SHA256 Algorithm = "sha256"
+	SHA512 Algorithm = "sha512"
+)
+
+// Algorithms lists the supported algorithms, strongest first.
+var Algorithms = []Algorithm{SHA512, SHA256}
+
+var (
+	ErrMissing  = errors.New("no checksum in manifest")
+	ErrMismatch = errors.New("checksum mismatch")
+)
+
+func ParseAlgorithm(name string) (Algorithm, error) {
+
+	for _, a := range Algorithms {
+		if strings.EqualFold(name, string(a)) {
+			return a, nil
+		}
+	}
+
+	return "", fmt.Errorf("unsupported checksum algorithm %q, use sha256 or sha512", name)
+}
+
+func (a Algorithm) New() hash.Hash {
+
+	if a == SHA512 {
+		return sha512.New()
+	}
+
+	return sha256.New()
+}
+
+// ManifestName returns the conventional asset name of the manifest, e.g. SHA256SUMS.
+func (a Algorithm) ManifestName() string {
+	return strings.ToUpper(string(a)) + "SUMS"
+}
+
+// File returns the hex digest of the file.
+func File(path string, a Algorithm) (string, error) {
+
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+
+	//noinspection GoUnhandledErrorResult
+	defer file.Close()
+
+	h := a.New()
+	if _, err = io.Copy(h, file); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// Manifest maps file names to hex digests.
+type Manifest map[string]string
+
+// ParseManifest reads "<digest>  <name>" lines, also accepting the "<digest> *<name>" binary mode marker.
+func ParseManifest(data []byte) (Manifest, error) {
+
+	m := Manifest{}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		fields := strings.SplitN(text, " ", 2)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("manifest line %d: expected \"<digest>  <name>\"", line)
+		}
+
+		digest := strings.ToLower(fields[0])
+		if _, err := hex.DecodeString(digest); err != nil {
+			return nil, fmt.Errorf("manifest line %d: invalid digest %q", line, fields[0])
+		}
+
+		name := strings.TrimPrefix(strings.TrimLeft(fields[1], " "), "*")
+		m[name] = digest
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// Merge adds the entries of other, replacing those with the same name.
+func (m Manifest) Merge(other Manifest) {
+	for name, digest := range other {
+		m[name] = digest
+	}
+}
+
+// Bytes returns the manifest sorted by name.
+func (m Manifest) Bytes() []byte {
+
+	var names []string
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b bytes.Buffer
+	for _, name := range names {
+		fmt.Fprintf(&b, "%s  %s\n", m[name], name)
+	}
+
+	return b.Bytes()
+}
+
+// Verify checks the local file at path against the digest recorded for name.
+func (m Manifest) Verify(name string, path string, a Algorithm) error {
+
+	expected, ok := m[name]
+	if !ok {
+		return fmt.Errorf("%s: %w", name, ErrMissing)
+	}
+
+	actual, err := File(path, a)
+	if err != nil {
+		return err
+	}
+
+	if actual != expected {
+		return fmt.Errorf("%s: %w: expected %s, got %s", name, ErrMismatch, expected, actual)
+	}
+
+	return nil
+}
@@ -0,0 +1,71 @@
+package checksum
+
+import (
+	"reflect"
+	"testing"
+)
+
+const (
+	digestA = "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"
+	digestB = "486ea46224d1bb4fb680f34f7c9ad96a8f24ec88be73ea8e5a6c65260e9cb8a7"
+)
+
+func TestParseManifest(t *testing.T) {
+
+	tests := []struct {
+		in   string
+		want Manifest
+		err  bool
+	}{
+		{in: "", want: Manifest{}},
+		{in: digestA + "  a.tar.gz\n", want: Manifest{"a.tar.gz": digestA}},
+		{in: digestA + "  a.tar.gz\n" + digestB + "  b.zip\n", want: Manifest{"a.tar.gz": digestA, "b.zip": digestB}},
+
+		// The binary mode marker of sha256sum -b, upper case digests, and no trailing newline.
+		{in: digestA + " *a.tar.gz", want: Manifest{"a.tar.gz": digestA}},
+		{in: "2CF24DBA5FB0A30E26E83B2AC5B9E29E1B161E5C1FA7425E73043362938B9824  a.tar.gz", want: Manifest{"a.tar.gz": digestA}},
+
+		// Blank lines, comments, CRLF line endings and names with spaces.
+		{in: "# release v1.2.3\n\n" + digestA + "  my app.tar.gz\r\n", want: Manifest{"my app.tar.gz": digestA}},
+
+		// A later line of the same name wins.
+		{in: digestA + "  a\n" + digestB + "  a\n", want: Manifest{"a": digestB}},
+
+		{in: digestA + "\n", err: true},
+		{in: "xyz  a.tar.gz\n", err: true},
+		{in: "abc  a.tar.gz\n", err: true},
+	}
+
+	for _, test := range tests {
+
+		got, err := ParseManifest([]byte(test.in))
+		if test.err {
+			if err == nil {
+				t.Errorf("ParseManifest(%q) = %v, want an error", test.in, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseManifest(%q) failed: %v", test.in, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("ParseManifest(%q) = %v, want %v", test.in, got, test.want)
+		}
+	}
+}
+
+func TestManifestBytes(t *testing.T) {
+
+	m := Manifest{"b.zip": digestB, "a.tar.gz": digestA}
+
+	want := digestA + "  a.tar.gz\n" + digestB + "  b.zip\n"
+	if got := string(m.Bytes()); got != want {
+		t.Errorf("Bytes() = %q, want %q", got, want)
+	}
+
+	parsed, err := ParseManifest(m.Bytes())
+	if err != nil || !reflect.DeepEqual(parsed, m) {
+		t.Errorf("ParseManifest(Bytes()) = %v, %v, want %v", parsed, err, m)
+	}
+}
@@ -0,0 +1,225 @@
+// Copyright © 2019 xykong <xy.kong@gmail.com>
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/xykong/github-release/github"
+	"github.com/xykong/github-release/utils"
+	"path/filepath"
+)
+
+// releaseManifest describes a release in the "release" section of the config file.
+type releaseManifest struct {
+	Tag             string          `mapstructure:"tag"`
+	TargetCommitish string          `mapstructure:"target_commitish"`
+	Name            string          `mapstructure:"name"`
+	Body            string          `mapstructure:"body"`
+	Draft           bool            `mapstructure:"draft"`
+	Prerelease      bool            `mapstructure:"prerelease"`
+	VersionFile     string          `mapstructure:"version_file"`
+	Assets          []assetManifest `mapstructure:"assets"`
+}
+
+// assetManifest describes the files of a glob pattern uploaded as assets.
+type assetManifest struct {
+	Path        string `mapstructure:"path"`
+	Name        string `mapstructure:"name"`
+	Label       string `mapstructure:"label"`
+	ContentType string `mapstructure:"content_type"`
+}
+
+// applyCmd represents the apply command
+var applyCmd = &cobra.Command{
+	Use:   "apply",
+	Short: "Create or update the release described in the config file.",
+	Long: `Create or update the release described in the "release" section of the config
+file, and upload its assets:
+
+  release:
+    tag: v{{.Version}}
+    name: github-release {{.Tag}}
+    body: Publish the release package.
+    draft: false
+    prerelease: false
+    version_file: VERSION          # .Version of the tag is its first word, default: VERSION
+    assets:
+      - path: dist/*_{{.Tag}}_*.tar.gz
+        label: '{{.File}}'
+        content_type: application/gzip
+      - path: build/github-release
+        name: github-release_{{.Tag}}_{{.Os}}_{{.Arch}}
+
+The values are Go templates, see Templates in the README. The tag is rendered
+with .Version, the first word of the version file without a leading v, and the
+others with the tag; the name and label of an asset also with .File, the base
+name of the matched file, and the name and body also with .Assets, the names of
+the uploaded assets. Every path must match at least one file.
+
+A new release is created as a draft and published once all assets are uploaded.
+An existing release of the tag is updated, and its assets with the names of the
+uploaded files are replaced.
+`,
+	Args: usageArgs(cobra.NoArgs),
+	RunE: func(cmd *cobra.Command, args []string) error {
+
+		bindFlags(cmd, "parallel", "keep-going", "checksum", "sign", "keep-draft", "plan")
+
+		owner := viper.GetString("user")
+		repo := viper.GetString("repo")
+
+		utils.Verbose("apply called: %v, %s, %s\n", args, owner, repo)
+
+		var manifest releaseManifest
+		if err := viper.UnmarshalKey("release", &manifest); err != nil {
+			return usageErrorf("invalid release section in the config file: %v", err)
+		}
+
+		request, files, err := manifest.resolve()
+		if err != nil {
+			return err
+		}
+
+		t := newTemplater(request.TagName)
+		if err := t.renderAssets(files); err != nil {
+			return err
+		}
+		if err := t.renderRelease(request); err != nil {
+			return err
+		}
+		// Unlike --body, the body of the config file is a template like its other values.
+		if request.Body, err = t.render("body", request.Body); err != nil {
+			return err
+		}
+
+		client := newClient()
+		edit := manifestEdit(request)
+
+		options, err := newUploadOptions()
+		if err != nil {
+			return err
+		}
+
+		if viper.GetBool("plan") {
+			return planShip(client, owner, repo, request, edit, files, options, true)
+		}
+
+		release, err := shipRelease(client, owner, repo, request, edit, files, options, true)
+		if err != nil {
+			return err
+		}
+
+		utils.Essential("%d", release.Id)
+		return nil
+	},
+}
+
+// resolve renders the manifest into the release request and the upload requests of the matched files. The
+// tag is rendered with the version of the version file, the other values with the tag; the names and labels
+// of the assets are left to renderAssets, and the name and body to the caller once Assets is known.
+func (m *releaseManifest) resolve() (*github.RequestCreateRelease, []*github.RequestUploadAsset, error) {
+
+	versionFile := m.VersionFile
+	if versionFile == "" {
+		versionFile = "VERSION"
+	}
+	word, err := readVersionFile(versionFile)
+	if err != nil && m.VersionFile != "" {
+		return nil, nil, &usageError{err: err}
+	}
+
+	tag, err := newTemplater(word).render("tag", m.Tag)
+	if err != nil {
+		return nil, nil, err
+	}
+	if tag == "" {
+		return nil, nil, usageErrorf("release.tag is required in the config file")
+	}
+
+	t := newTemplater(tag)
+	request := &github.RequestCreateRelease{
+		TagName:    tag,
+		Name:       m.Name,
+		Body:       m.Body,
+		Draft:      m.Draft,
+		Prerelease: m.Prerelease,
+	}
+	if request.TargetCommitish, err = t.render("target_commitish", m.TargetCommitish); err != nil {
+		return nil, nil, err
+	}
+
+	var files []*github.RequestUploadAsset
+	for _, asset := range m.Assets {
+
+		pattern, err := t.render("asset path", asset.Path)
+		if err != nil {
+			return nil, nil, err
+		}
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, nil, usageErrorf("invalid asset path %q: %v", pattern, err)
+		}
+		if len(matches) == 0 {
+			return nil, nil, usageErrorf("asset path %q matches no file", pattern)
+		}
+
+		for _, path := range matches {
+			files = append(files, &github.RequestUploadAsset{
+				Filename:    path,
+				Name:        asset.Name,
+				Label:       asset.Label,
+				ContentType: asset.ContentType,
+			})
+		}
+	}
+
+	return request, files, nil
+}
+
+// manifestEdit changes an existing release to the manifest: its draft and prerelease flags always, the other
+// fields when the manifest gives them.
+func manifestEdit(request *github.RequestCreateRelease) *github.RequestEditRelease {
+
+	edit := &github.RequestEditRelease{
+		Draft:      github.Bool(request.Draft),
+		Prerelease: github.Bool(request.Prerelease),
+	}
+
+	if request.TargetCommitish != "" {
+		edit.TargetCommitish = github.String(request.TargetCommitish)
+	}
+	if request.Name != "" {
+		edit.Name = github.String(request.Name)
+	}
+	if request.Body != "" {
+		edit.Body = github.String(request.Body)
+	}
+
+	return edit
+}
+
+func init() {
+	rootCmd.AddCommand(applyCmd)
+
+	applyCmd.PersistentFlags().IntP("parallel", "p", 1, "Number of files uploaded at the same time")
+	_ = viper.BindPFlag("parallel", applyCmd.PersistentFlags().Lookup("parallel"))
+
+	applyCmd.PersistentFlags().BoolP("keep-going", "k", false, "Continue uploading the remaining files after a failure")
+	_ = viper.BindPFlag("keep-going", applyCmd.PersistentFlags().Lookup("keep-going"))
+
+	applyCmd.PersistentFlags().StringP("checksum", "", "", "Publish a SHA256SUMS or SHA512SUMS manifest of the files: sha256 or sha512")
+	_ = viper.BindPFlag("checksum", applyCmd.PersistentFlags().Lookup("checksum"))
+
+	applyCmd.PersistentFlags().BoolP("sign", "", false, "Upload detached signatures (.asc, .sig) of the files and the checksum manifest, with the keys of the config file")
+	_ = viper.BindPFlag("sign", applyCmd.PersistentFlags().Lookup("sign"))
+
+	applyCmd.PersistentFlags().BoolP("keep-draft", "", false, "Keep a new draft release when an upload fails")
+	_ = viper.BindPFlag("keep-draft", applyCmd.PersistentFlags().Lookup("keep-draft"))
+
+	addTemplateFlag(applyCmd, "Render the body of the config file as a template, see Templates in the README")
+	_ = applyCmd.PersistentFlags().MarkDeprecated("template", "the body of the config file is always rendered")
+
+	applyCmd.PersistentFlags().BoolP("plan", "", false, "Print the changes to the release instead of making them")
+	_ = viper.BindPFlag("plan", applyCmd.PersistentFlags().Lookup("plan"))
+}
@@ -0,0 +1,167 @@
+// Copyright © 2019 xykong <xy.kong@gmail.com>
+
+package cmd
+
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/xykong/github-release/git"
+	"github.com/xykong/github-release/utils"
+	"io/ioutil"
+	"os"
+	"os/exec"
+)
+
+// readBody returns the body given by --body, or else by the config file, by --body-file, --notes-from-git or
+// --editor, or nil when none of them is given. With --template the given body, and not the notes, is rendered
+// as a template of the tag. The editor starts with the given body, or else with the current body of the
+// release, which is only fetched then. Neither the current body nor the edited one is rendered, so text
+// fetched from GitHub is never executed.
+func readBody(cmd *cobra.Command, t *templater, body string, current func() (string, error)) (*string, error) {
+
+	bodyFile := viper.GetString("body-file")
+	editor := viper.GetBool("editor")
+	flagged := cmd.Flags().Changed("body")
+	notes := cmd.Flags().Lookup("notes-from-git") != nil && viper.GetBool("notes-from-git")
+
+	if flagged && bodyFile != "" {
+		return nil, usageErrorf("--body and --body-file exclude each other")
+	}
+	if notes && (flagged || bodyFile != "") {
+		return nil, usageErrorf("--notes-from-git excludes --body and --body-file")
+	}
+	if flagged {
+		body, _ = cmd.Flags().GetString("body")
+	}
+
+	given := true
+	switch {
+	case notes:
+		var err error
+		if body, err = gitNotes(t.tag); err != nil {
+			return nil, err
+		}
+		given = false
+
+	case bodyFile == "-":
+		data, err := ioutil.ReadAll(os.Stdin)
+		if err != nil {
+			return nil, fmt.Errorf("read body from stdin: %w", err)
+		}
+		body = string(data)
+
+	case bodyFile != "":
+		data, err := ioutil.ReadFile(bodyFile)
+		if err != nil {
+			return nil, &usageError{err: err}
+		}
+		body = string(data)
+
+	case flagged || body != "":
+
+	case editor:
+		var err error
+		if body, err = current(); err != nil {
+			return nil, err
+		}
+		given = false
+
+	default:
+		return nil, nil
+	}
+
+	if given && viper.GetBool("template") {
+		var err error
+		if body, err = t.render("body", body); err != nil {
+			return nil, err
+		}
+	}
+
+	if editor {
+		var err error
+		if body, err = editBody(body); err != nil {
+			return nil, err
+		}
+	}
+
+	return &body, nil
+}
+
+// gitNotes returns the changelog of the commits since the tag before the given one, read from the local git
+// repository.
+func gitNotes(tag string) (string, error) {
+
+	notes, previous, err := git.Changelog(tag)
+	if err != nil {
+		return "", err
+	}
+
+	from := previous
+	if from == "" {
+		from = "the first commit"
+	}
+	utils.Infof(utils.Fields{"tag_name": tag, "previous": previous}, "release notes since %s", from)
+
+	return notes, nil
+}
+
+// editBody opens the body in $VISUAL or $EDITOR, like git commit, and returns the saved contents.
+func editBody(body string) (string, error) {
+
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+
+	file, err := ioutil.TempFile("", "RELEASE_BODY_*.md")
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = os.Remove(file.Name()) }()
+
+	_, err = file.WriteString(body)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", err
+	}
+
+	// The editor is run by the shell, as it may come with arguments, e.g. "code --wait".
+	command := exec.Command("sh", "-c", editor+` "$@"`, editor, file.Name())
+	command.Stdin = os.Stdin
+	command.Stdout = os.Stdout
+	command.Stderr = os.Stderr
+	if err := command.Run(); err != nil {
+		return "", fmt.Errorf("editor %s: %w", editor, err)
+	}
+
+	data, err := ioutil.ReadFile(file.Name())
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
+
+// addBodyFlags adds --body-file, --editor and --template to a command with a --body flag.
+func addBodyFlags(cmd *cobra.Command) {
+
+	cmd.PersistentFlags().StringP("body-file", "", "", "Read the body from the file, - for stdin")
+	_ = viper.BindPFlag("body-file", cmd.PersistentFlags().Lookup("body-file"))
+
+	cmd.PersistentFlags().BoolP("editor", "", false, "Edit the body in $EDITOR, starting with the given or current body")
+	_ = viper.BindPFlag("editor", cmd.PersistentFlags().Lookup("editor"))
+
+	addTemplateFlag(cmd, "Render the body of --body or --body-file as a template, see Templates in the README")
+}
+
+// addNotesFlag adds --notes-from-git to a command creating the release of --tag_name.
+func addNotesFlag(cmd *cobra.Command) {
+	cmd.PersistentFlags().BoolP("notes-from-git", "", false, "Use the changelog of the Conventional Commits since the previous tag as the body")
+	_ = viper.BindPFlag("notes-from-git", cmd.PersistentFlags().Lookup("notes-from-git"))
+}
@@ -0,0 +1,255 @@
+// Copyright © 2019 xykong <xy.kong@gmail.com>
+
+package cmd
+
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/xykong/github-release/git"
+	"github.com/xykong/github-release/semver"
+	"github.com/xykong/github-release/utils"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// bumpRule rewrites the matches of a regular expression in a file.
+type bumpRule struct {
+	Path    string `mapstructure:"path"`
+	Pattern string `mapstructure:"pattern"`
+	Replace string `mapstructure:"replace"`
+}
+
+// bumpCmd represents the bump command
+var bumpCmd = &cobra.Command{
+	Use:       "bump major|minor|patch|prerelease|build",
+	Short:     "Bump the semantic version of the VERSION file and the files that mention it.",
+	ValidArgs: []string{"major", "minor", "patch", "prerelease", "build"},
+	Long: `Bump the version of the VERSION file, its first word, e.g. v1.2.3, as a semantic
+version (https://semver.org), and rewrite the files of the config file:
+
+  bump:
+    version_file: VERSION          # default: VERSION
+    files:
+      - path: VERSION
+        pattern: ^\S+              # a regular expression, $1 expands its groups
+        replace: v{{.Version}}     # required with a pattern, a template, see Templates in the README
+      - path: README.md            # without a pattern, the version is replaced
+
+major, minor and patch increment their part and release a pre-release, e.g.
+1.3.0-rc.1 bumps to 1.3.0. prerelease increments the pre-release, e.g. 1.3.0-rc.1
+to 1.3.0-rc.2, or starts one of the next patch, 1.2.3 to 1.2.4-rc.1. build sets
+the build metadata to --build, default: the commit checked out.
+
+Every rule has to match. With --dry-run the changes are printed as a diff.
+`,
+	Args: usageArgs(cobra.ExactArgs(1)),
+	RunE: func(cmd *cobra.Command, args []string) error {
+
+		bindFlags(cmd, "preid", "build", "file")
+
+		utils.Verbose("bump called: %v\n", args)
+
+		versionFile := viper.GetString("bump.version_file")
+		if versionFile == "" {
+			versionFile = "VERSION"
+		}
+
+		word, err := readVersionFile(versionFile)
+		if err != nil {
+			return &usageError{err: err}
+		}
+		if word == "" {
+			return usageErrorf("%s has no version", versionFile)
+		}
+
+		prefix := ""
+		if strings.HasPrefix(word, "v") {
+			prefix = "v"
+		}
+
+		current, err := semver.Parse(strings.TrimPrefix(word, "v"))
+		if err != nil {
+			return fmt.Errorf("%s: %w", versionFile, err)
+		}
+
+		next, err := bumpVersion(current, args[0])
+		if err != nil {
+			return err
+		}
+
+		rules, err := bumpRules(versionFile)
+		if err != nil {
+			return err
+		}
+
+		t := newTemplater(prefix + next.String())
+
+		// All files are rewritten in memory first, so a rule that does not match changes nothing.
+		contents := make([]string, len(rules))
+		originals := make([]string, len(rules))
+		for i, rule := range rules {
+			if originals[i], contents[i], err = rule.apply(t, current, next); err != nil {
+				return err
+			}
+		}
+
+		for i, rule := range rules {
+
+			if viper.GetBool("dry-run") {
+				utils.PrintDiffContext(rule.Path, rule.Path+" (bumped)",
+					strings.Split(originals[i], "\n"), strings.Split(contents[i], "\n"), 2)
+				continue
+			}
+
+			info, err := os.Stat(rule.Path)
+			if err != nil {
+				return err
+			}
+			if err := ioutil.WriteFile(rule.Path, []byte(contents[i]), info.Mode()); err != nil {
+				return err
+			}
+
+			utils.Verbose("update %s\n", rule.Path)
+		}
+
+		utils.Infof(utils.Fields{
+			"from": word,
+			"to":   prefix + next.String(),
+		}, "bump version success")
+
+		utils.Essential("%s", prefix+next.String())
+		return nil
+	},
+	Example: `github-release bump patch
+github-release bump prerelease --preid beta --dry-run`,
+}
+
+// bumpVersion returns the next version of the part, or the version with the build metadata of --build.
+func bumpVersion(current semver.Version, part string) (semver.Version, error) {
+
+	if part == "build" {
+
+		build := viper.GetString("build")
+		if build == "" {
+			commit, err := git.Head()
+			if err != nil {
+				return semver.Version{}, usageErrorf("--build is required outside of a git repository")
+			}
+			build = commit[:7]
+		}
+
+		base := current
+		base.Build = nil
+		next, err := semver.Parse(base.String() + "+" + build)
+		if err != nil {
+			return semver.Version{}, &usageError{err: err}
+		}
+
+		return next, nil
+	}
+
+	for _, p := range semver.Parts {
+		if string(p) == part {
+			next, err := current.Bump(p, viper.GetString("preid"))
+			if err != nil {
+				return semver.Version{}, &usageError{err: err}
+			}
+			return next, nil
+		}
+	}
+
+	return semver.Version{}, usageErrorf("unknown version part %q, use major, minor, patch, prerelease or build", part)
+}
+
+// bumpRules returns the rules of --file, of the config file, or else the rule of the version file.
+func bumpRules(versionFile string) ([]bumpRule, error) {
+
+	var rules []bumpRule
+	if files := viper.GetStringSlice("file"); len(files) > 0 {
+		for _, path := range files {
+			rules = append(rules, bumpRule{Path: path})
+		}
+		return rules, nil
+	}
+
+	if err := viper.UnmarshalKey("bump.files", &rules); err != nil {
+		return nil, usageErrorf("invalid bump section in the config file: %v", err)
+	}
+	if len(rules) == 0 {
+		rules = []bumpRule{{Path: versionFile}}
+	}
+
+	for _, rule := range rules {
+		if rule.Path == "" {
+			return nil, usageErrorf("bump.files has a rule without a path")
+		}
+		// An empty replace would delete the matches of the pattern.
+		if rule.Pattern != "" && rule.Replace == "" {
+			return nil, usageErrorf("bump.files rule of %s has a pattern without a replace", rule.Path)
+		}
+	}
+
+	return rules, nil
+}
+
+// apply returns the file as it is and as rewritten by the rule. A rule without a pattern replaces the
+// current version, and not a version it is a part of, e.g. 1.2.3 in 11.2.3.
+func (r *bumpRule) apply(t *templater, current semver.Version, next semver.Version) (string, string, error) {
+
+	data, err := ioutil.ReadFile(r.Path)
+	if err != nil {
+		return "", "", &usageError{err: err}
+	}
+	original := string(data)
+
+	pattern := `(^|[^0-9.])` + regexp.QuoteMeta(current.String()) + `\b`
+	replace := "${1}" + next.String()
+	if r.Pattern != "" {
+		pattern = r.Pattern
+		if replace, err = t.render("replace", r.Replace); err != nil {
+			return "", "", err
+		}
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return "", "", usageErrorf("invalid pattern of %s: %v", r.Path, err)
+	}
+
+	if !re.MatchString(original) {
+		return "", "", fmt.Errorf("%s: %q matches nothing", r.Path, pattern)
+	}
+
+	return original, re.ReplaceAllString(original, replace), nil
+}
+
+// readVersionFile returns the first word of the version file, e.g. v1.2.3, empty when the file is.
+func readVersionFile(path string) (string, error) {
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	if fields := strings.Fields(string(data)); len(fields) > 0 {
+		return fields[0], nil
+	}
+
+	return "", nil
+}
+
+func init() {
+	rootCmd.AddCommand(bumpCmd)
+
+	bumpCmd.PersistentFlags().StringP("preid", "", "", "The pre-release of prerelease, e.g. beta (default rc)")
+	_ = viper.BindPFlag("preid", bumpCmd.PersistentFlags().Lookup("preid"))
+
+	bumpCmd.PersistentFlags().StringP("build", "", "", "The build metadata of build, e.g. 20190630.1 (default the short commit)")
+	_ = viper.BindPFlag("build", bumpCmd.PersistentFlags().Lookup("build"))
+
+	bumpCmd.PersistentFlags().StringSliceP("file", "f", nil, "Replace the version in the files instead of applying the rules of the config file")
+	_ = viper.BindPFlag("file", bumpCmd.PersistentFlags().Lookup("file"))
+}
@@ -0,0 +1,175 @@
+// Copyright © 2019 xykong <xy.kong@gmail.com>
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/xykong/github-release/checksum"
+	"github.com/xykong/github-release/github"
+	"github.com/xykong/github-release/sign"
+	"github.com/xykong/github-release/utils"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// findAsset returns the asset with the name, or nil.
+func findAsset(assets github.Assets, name string) *github.Asset {
+
+	for i := range assets {
+		if assets[i].Name == name {
+			return &assets[i]
+		}
+	}
+
+	return nil
+}
+
+// isManifest reports whether the asset is a checksum manifest.
+func isManifest(name string) bool {
+
+	for _, algorithm := range checksum.Algorithms {
+		if name == algorithm.ManifestName() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// remoteManifest fetches the strongest checksum manifest among the assets of a release.
+// When checker is not nil the manifest must carry a valid signature.
+func remoteManifest(client *github.Client, owner string, repo string, assets github.Assets,
+	checker *signatureChecker) (checksum.Manifest, checksum.Algorithm, error) {
+
+	for _, algorithm := range checksum.Algorithms {
+
+		asset := findAsset(assets, algorithm.ManifestName())
+		if asset == nil {
+			continue
+		}
+
+		data, err := client.FetchAsset(owner, repo, asset)
+		if err != nil {
+			return nil, "", err
+		}
+
+		if checker != nil {
+			err = checker.verify(asset.Name, func() (io.ReadCloser, error) {
+				return ioutil.NopCloser(bytes.NewReader(data)), nil
+			})
+			if err != nil {
+				return nil, "", err
+			}
+		}
+
+		manifest, err := checksum.ParseManifest(data)
+		if err != nil {
+			return nil, "", fmt.Errorf("%s: %w", asset.Name, err)
+		}
+
+		return manifest, algorithm, nil
+	}
+
+	return nil, "", fmt.Errorf("release has no checksum manifest: %w", errNotFound)
+}
+
+// publishManifest records the digests of the uploaded files in the manifest asset of the release,
+// merging them into the entries of an existing manifest. The manifest is signed by each of the signers.
+func publishManifest(client *github.Client, owner string, repo string, release *github.Release,
+	algorithm checksum.Algorithm, signers []sign.Signer, results []github.UploadResult) error {
+
+	manifest := checksum.Manifest{}
+	for _, r := range results {
+		if r.Err != nil {
+			continue
+		}
+
+		digest, err := checksum.File(r.Request.Filename, algorithm)
+		if err != nil {
+			return err
+		}
+		manifest[r.Asset.Name] = digest
+	}
+
+	if len(manifest) == 0 {
+		return nil
+	}
+
+	name := algorithm.ManifestName()
+
+	assets, err := client.ListAssets(owner, repo, release.Id, nil)
+	if err != nil {
+		return err
+	}
+
+	if existing := findAsset(assets, name); existing != nil {
+
+		data, err := client.FetchAsset(owner, repo, existing)
+		if err != nil {
+			return err
+		}
+
+		merged, err := checksum.ParseManifest(data)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		merged.Merge(manifest)
+		manifest = merged
+	}
+
+	dir, err := ioutil.TempDir("", "github-release")
+	if err != nil {
+		return err
+	}
+
+	//noinspection GoUnhandledErrorResult
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, name)
+	if err = ioutil.WriteFile(path, manifest.Bytes(), 0644); err != nil {
+		return err
+	}
+
+	sigRequests, err := signFile(signers, path, name, dir, "")
+	if err != nil {
+		return err
+	}
+	requests := append([]*github.RequestUploadAsset{{Filename: path}}, sigRequests...)
+
+	// The manifest and its signatures are all uploaded before the old ones, whose signatures no longer
+	// match, are replaced, so the release never lacks a manifest.
+	replacements := stageReplacements(assets, requests)
+
+	uploaded := make([]*github.Asset, len(requests))
+	for i, request := range requests {
+		if uploaded[i], err = client.UploadReleaseAsset(owner, repo, release, request); err != nil {
+			return err
+		}
+	}
+
+	for i, request := range requests {
+		if replaced := replacements[request]; replaced != nil {
+			if uploaded[i], err = replaced.swap(client, owner, repo, uploaded[i]); err != nil {
+				return err
+			}
+		}
+	}
+
+	utils.Infof(utils.Fields{
+		"id":      uploaded[0].Id,
+		"name":    uploaded[0].Name,
+		"entries": len(manifest),
+	}, "publish checksum manifest success")
+
+	for _, asset := range uploaded[1:] {
+		utils.Infof(utils.Fields{
+			"id":   asset.Id,
+			"name": asset.Name,
+		}, "publish checksum manifest signature success")
+	}
+
+	return nil
+}
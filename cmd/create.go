@@ -3,10 +3,11 @@
 package cmd
 
 import (
+	"fmt"
 	"github.com/spf13/viper"
+	"github.com/xykong/github-release/git"
 	"github.com/xykong/github-release/github"
 	"github.com/xykong/github-release/utils"
-	"os"
 
 	"github.com/spf13/cobra"
 )
@@ -20,26 +21,228 @@ using this endpoint may result in abuse rate limiting.
 See "Abuse rate limits" and "Dealing with abuse rate limits" 
 for details.
 `,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 
-		_ = viper.BindPFlag("id", cmd.PersistentFlags().Lookup("id"))
+		bindFlags(cmd, "id", "token", "tag_name", "target_commitish", "name", "body", "draft", "prerelease", "body-file", "editor", "template", "notes-from-git", "plan", "edit", "upsert", "from-git", "force")
 
 		owner := viper.GetString("user")
 		repo := viper.GetString("repo")
 
 		utils.Verbose("create called: %v, %s, %s\n", args, owner, repo)
 
-		err := github.CreateRelease(owner, repo)
+		request := &github.RequestCreateRelease{
+			TagName:         viper.GetString("tag_name"),
+			TargetCommitish: viper.GetString("target_commitish"),
+			Name:            viper.GetString("name"),
+			Body:            viper.GetString("body"),
+			Draft:           viper.GetBool("draft"),
+			Prerelease:      viper.GetBool("prerelease"),
+		}
+
+		if viper.GetBool("edit") && viper.GetBool("upsert") {
+			return usageErrorf("--edit and --upsert exclude each other")
+		}
+
+		if viper.GetBool("from-git") {
+			if viper.GetBool("edit") {
+				return usageErrorf("--from-git and --edit exclude each other")
+			}
+			if cmd.Flags().Changed("target_commitish") {
+				return usageErrorf("--from-git and --target_commitish exclude each other")
+			}
+			if err := releaseFromGit(request); err != nil {
+				return err
+			}
+		}
+
+		if viper.GetBool("upsert") && request.TagName == "" {
+			return usageErrorf("tag_name is required with --upsert")
+		}
+
+		client := newClient()
+
+		if viper.GetBool("edit") {
+			return createEdit(cmd, client, owner, repo)
+		}
+
+		t := newTemplater(request.TagName)
+		body, err := readBody(cmd, t, request.Body, func() (string, error) {
+			if viper.GetBool("upsert") {
+				release, err := client.FindRelease(owner, repo, request.TagName)
+				if err != nil || release == nil {
+					return request.Body, err
+				}
+				return release.Body, nil
+			}
+			return request.Body, nil
+		})
 		if err != nil {
-			utils.Error("create called: %v", err)
-			os.Exit(1)
+			return err
+		}
+		if body != nil {
+			request.Body = *body
+		}
+
+		if err := t.renderRelease(request); err != nil {
+			return err
 		}
+
+		// An existing release is only changed in the fields given on the command line.
+		edit := editRequest(cmd)
+		edit.Body = body
+		if edit.Name != nil {
+			edit.Name = &request.Name
+		}
+
+		if viper.GetBool("plan") {
+			return planCreate(client, owner, repo, request, edit)
+		}
+
+		desc := "create a release"
+		var release *github.Release
+		if viper.GetBool("upsert") {
+			var created bool
+			if release, created, err = client.UpsertRelease(owner, repo, request, edit); err != nil {
+				return err
+			}
+			if !created {
+				desc = "update a release"
+			}
+		} else {
+			if release, err = client.CreateRelease(owner, repo, request); err != nil {
+				return err
+			}
+		}
+
+		utils.Infof(utils.Fields{
+			"id":       release.Id,
+			"tag_name": release.TagName,
+			"url":      release.Url,
+		}, "%s success", desc)
+
+		utils.Essential("%d", release.Id)
+		return nil
 	},
 	Example: `github-release create --tag_name v0.0.1\
                       --name "The name of the release."\
                       --body "Text describing the contents of the tag."`,
 }
 
+// releaseFromGit sets the tag of the request, when it is not given, to the tag of HEAD, and the target to
+// the commit of the tag. It fails when the working tree has uncommitted changes, or the tag is not on HEAD,
+// unless --force is given; then a HEAD without a tag is released as the name git describe gives it.
+func releaseFromGit(request *github.RequestCreateRelease) error {
+
+	force := viper.GetBool("force")
+
+	head, err := git.Head()
+	if err != nil {
+		return err
+	}
+
+	dirty, err := git.Dirty()
+	if err != nil {
+		return err
+	}
+	if dirty && !force {
+		return fmt.Errorf("the working tree has uncommitted changes, commit them or give --force: %w", errConflict)
+	}
+
+	if request.TagName == "" {
+		tag, err := git.Describe(true)
+		if err == git.ErrNoTag {
+			if tag, err = git.Describe(false); err == git.ErrNoTag {
+				return usageErrorf("no tag precedes HEAD, tag_name is required")
+			}
+			if !force {
+				return fmt.Errorf("HEAD is not tagged, give --force to release it as %s: %w", tag, errConflict)
+			}
+		}
+		if err != nil {
+			return err
+		}
+		request.TagName = tag
+	}
+
+	target := head
+	if git.HasRevision("refs/tags/" + request.TagName) {
+		if target, err = git.CommitOf("refs/tags/" + request.TagName); err != nil {
+			return err
+		}
+		if target != head && !force {
+			return fmt.Errorf("tag %s is on %s, not on HEAD %s, give --force to release it anyway: %w",
+				request.TagName, target[:7], head[:7], errConflict)
+		}
+	}
+	request.TargetCommitish = target
+
+	utils.Verbose("Using tag %s of commit %s\n", request.TagName, target)
+	return nil
+}
+
+// createEdit edits the release of --id, sending only the fields given, as the edit command does.
+func createEdit(cmd *cobra.Command, client *github.Client, owner string, repo string) error {
+
+	release, err := findEditedRelease(cmd, client, owner, repo)
+	if err != nil {
+		return err
+	}
+
+	request := editRequest(cmd)
+	t := editTemplater(release, request)
+	if request.Body, err = readBody(cmd, t, "", func() (string, error) { return release.Body, nil }); err != nil {
+		return err
+	}
+	if err := renderEdit(t, request); err != nil {
+		return err
+	}
+
+	if viper.GetBool("plan") {
+		return planEdit(client, owner, repo, release, request)
+	}
+
+	if release, err = client.UpdateRelease(owner, repo, release.Id, request); err != nil {
+		return err
+	}
+
+	utils.Infof(utils.Fields{
+		"id":       release.Id,
+		"tag_name": release.TagName,
+		"url":      release.Url,
+	}, "edit a release success")
+
+	utils.Essential("%d", release.Id)
+	return nil
+}
+
+// planCreate prints the changes create is about to make to the release, with --upsert those of edit to an
+// existing one.
+func planCreate(client *github.Client, owner string, repo string, request *github.RequestCreateRelease,
+	edit *github.RequestEditRelease) error {
+
+	p, err := findPlan(client, owner, repo, request.TagName)
+	if err != nil {
+		return err
+	}
+
+	if p.release != nil && viper.GetBool("upsert") {
+		request = editedRelease(p.release, edit)
+	}
+
+	planned, _, err := p.planned(request, nil, nil)
+	if err != nil {
+		return err
+	}
+
+	p.print(planned)
+
+	if p.release != nil && !viper.GetBool("upsert") {
+		fmt.Printf("# a release of %s exists, create fails\n", request.TagName)
+	}
+
+	return nil
+}
+
 func init() {
 	rootCmd.AddCommand(createCmd)
 
@@ -63,6 +266,9 @@ func init() {
 	createCmd.PersistentFlags().StringP("body", "", "", "The tag of the release")
 	_ = viper.BindPFlag("body", createCmd.PersistentFlags().Lookup("body"))
 
+	addBodyFlags(createCmd)
+	addNotesFlag(createCmd)
+
 	createCmd.PersistentFlags().BoolP("draft", "", false, "The tag of the release")
 	_ = viper.BindPFlag("draft", createCmd.PersistentFlags().Lookup("draft"))
 
@@ -71,10 +277,23 @@ func init() {
 
 	createCmd.PersistentFlags().BoolP("edit", "e", false, "Users with push access to the repository can edit a release.")
 	_ = viper.BindPFlag("edit", createCmd.PersistentFlags().Lookup("edit"))
+	_ = createCmd.PersistentFlags().MarkDeprecated("edit", "use the edit command instead")
 
 	createCmd.PersistentFlags().StringP("id", "i", "", "The id of the release")
 	_ = viper.BindPFlag("id", createCmd.PersistentFlags().Lookup("id"))
 
+	createCmd.PersistentFlags().BoolP("upsert", "", false, "Edit the release of the tag when there is one, keeping the fields not given, and create it otherwise")
+	_ = viper.BindPFlag("upsert", createCmd.PersistentFlags().Lookup("upsert"))
+
+	createCmd.PersistentFlags().BoolP("from-git", "", false, "Release the tag of HEAD, or tag_name, of the local git repository, with the commit of the tag as target_commitish")
+	_ = viper.BindPFlag("from-git", createCmd.PersistentFlags().Lookup("from-git"))
+
+	createCmd.PersistentFlags().BoolP("force", "", false, "With --from-git, release uncommitted changes, a tag not on HEAD, or an untagged HEAD as named by git describe")
+	_ = viper.BindPFlag("force", createCmd.PersistentFlags().Lookup("force"))
+
+	createCmd.PersistentFlags().BoolP("plan", "", false, "Print the changes to the release instead of making them")
+	_ = viper.BindPFlag("plan", createCmd.PersistentFlags().Lookup("plan"))
+
 	// Cobra supports local flags which will only run when this command
 	// is called directly, e.g.:
 	// createCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
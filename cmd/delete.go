@@ -3,9 +3,8 @@
 package cmd
 
 import (
-	"fmt"
 	"github.com/spf13/viper"
-	"github.com/xykong/github-release/github"
+	"github.com/xykong/github-release/utils"
 
 	"github.com/spf13/cobra"
 )
@@ -20,16 +19,28 @@ and usage of using your command. For example:
 Cobra is a CLI library for Go that empowers applications.
 This application is a tool to generate the needed files
 to quickly create a Cobra application.`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 
 		_ = viper.BindPFlag("id", cmd.PersistentFlags().Lookup("id"))
 
 		owner := viper.GetString("user")
 		repo := viper.GetString("repo")
 
-		fmt.Printf("delete called: %v, %s, %s\n", args, owner, repo)
+		utils.Verbose("delete called: %v, %s, %s\n", args, owner, repo)
 
-		github.DeleteRelease(owner, repo)
+		id, err := releaseId()
+		if err != nil {
+			return err
+		}
+
+		if err = newClient().DeleteRelease(owner, repo, id); err != nil {
+			return err
+		}
+
+		utils.Infof(utils.Fields{
+			"id": id,
+		}, "delete a release success")
+		return nil
 	},
 }
 
@@ -0,0 +1,214 @@
+// Copyright © 2019 xykong <xy.kong@gmail.com>
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/xykong/github-release/checksum"
+	"github.com/xykong/github-release/github"
+	"github.com/xykong/github-release/sign"
+	"github.com/xykong/github-release/utils"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+// downloadCmd represents the download command
+var downloadCmd = &cobra.Command{
+	Use:   "download [pattern...]",
+	Short: "Download the assets of a release.",
+	Long: `Download the assets of the release given by --id or --tag, or of the latest release.
+
+Assets are selected by name with shell glob patterns, or regular expressions
+with --regex. Without patterns all assets of the release are downloaded.
+
+Assets are fetched through the API, so the token gives access to the assets
+of private repositories. Interrupted downloads are resumed.
+
+With --verify every asset is checked against the SHA512SUMS or SHA256SUMS
+manifest of the release, and files that do not match are not written.
+
+With --verify-signature every asset must carry a valid .asc or .sig signature
+made by a key of sign.pgp_keyring or sign.allowed_signers in the config file.
+Combined with --verify, the signature of the checksum manifest is checked
+instead for assets that are not signed themselves.
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+
+		_ = viper.BindPFlag("id", cmd.PersistentFlags().Lookup("id"))
+		_ = viper.BindPFlag("tag", cmd.PersistentFlags().Lookup("tag"))
+
+		owner := viper.GetString("user")
+		repo := viper.GetString("repo")
+		dir := viper.GetString("dir")
+
+		utils.Verbose("download called: %v, %s, %s\n", args, owner, repo)
+
+		match, err := assetMatcher(args, viper.GetBool("regex"))
+		if err != nil {
+			return err
+		}
+
+		client := newClient()
+
+		release, err := selectRelease(client, owner, repo)
+		if err != nil {
+			return err
+		}
+
+		assets, err := client.ListAssets(owner, repo, release.Id, nil)
+		if err != nil {
+			return err
+		}
+
+		var selected github.Assets
+		for _, asset := range assets {
+			if match(asset.Name) {
+				selected = append(selected, asset)
+			}
+		}
+
+		if len(selected) == 0 {
+			return fmt.Errorf("no asset of release %s matches %v: %w", release.TagName, args, errNotFound)
+		}
+
+		var checker *signatureChecker
+		if viper.GetBool("verify-signature") {
+			verifiers, err := newVerifiers()
+			if err != nil {
+				return err
+			}
+			checker = &signatureChecker{client: client, owner: owner, repo: repo, assets: assets, verifiers: verifiers}
+		}
+
+		var manifest checksum.Manifest
+		var algorithm checksum.Algorithm
+		if viper.GetBool("verify") {
+			if manifest, algorithm, err = remoteManifest(client, owner, repo, assets, checker); err != nil {
+				return err
+			}
+		}
+
+		verify := func(name string) func(string) error {
+			return func(path string) error {
+
+				if manifest != nil && !isManifest(name) {
+					if err := manifest.Verify(name, path, algorithm); err != nil {
+						return err
+					}
+				}
+
+				if checker == nil {
+					return nil
+				}
+
+				err := checker.verify(name, func() (io.ReadCloser, error) {
+					return os.Open(path)
+				})
+
+				// The digest was checked against the signed manifest.
+				if errors.Is(err, sign.ErrUnsigned) && manifest != nil && !isManifest(name) {
+					return nil
+				}
+
+				return err
+			}
+		}
+
+		if err = os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+
+		for i := range selected {
+			asset := &selected[i]
+			path := filepath.Join(dir, asset.Name)
+
+			request := &github.RequestDownloadAsset{Path: path}
+			if !isSignature(asset.Name) && (checker != nil || manifest != nil && !isManifest(asset.Name)) {
+				request.Verify = verify(asset.Name)
+			}
+
+			progress := utils.NewProgress(asset.Name, true)
+			request.Progress = progress.Update
+			err = client.DownloadAsset(owner, repo, asset, request)
+			progress.Done()
+			if err != nil {
+				return err
+			}
+
+			utils.Infof(utils.Fields{
+				"id":   asset.Id,
+				"size": utils.FormatBytes(asset.Size),
+				"path": path,
+			}, "download a release asset success")
+		}
+
+		return nil
+	},
+}
+
+// assetMatcher matches asset names against any of the glob patterns, or regular expressions.
+func assetMatcher(patterns []string, regex bool) (func(name string) bool, error) {
+
+	if len(patterns) == 0 {
+		return func(string) bool { return true }, nil
+	}
+
+	var matchers []func(string) bool
+	for _, pattern := range patterns {
+
+		if regex {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return nil, usageErrorf("invalid regular expression %q: %v", pattern, err)
+			}
+			matchers = append(matchers, re.MatchString)
+			continue
+		}
+
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return nil, usageErrorf("invalid pattern %q: %v", pattern, err)
+		}
+
+		pattern := pattern
+		matchers = append(matchers, func(name string) bool {
+			ok, _ := filepath.Match(pattern, name)
+			return ok
+		})
+	}
+
+	return func(name string) bool {
+		for _, match := range matchers {
+			if match(name) {
+				return true
+			}
+		}
+		return false
+	}, nil
+}
+
+func init() {
+	rootCmd.AddCommand(downloadCmd)
+
+	downloadCmd.PersistentFlags().StringP("id", "i", "", "The id of the release, default: the latest release")
+	_ = viper.BindPFlag("id", downloadCmd.PersistentFlags().Lookup("id"))
+
+	downloadCmd.PersistentFlags().StringP("tag", "", "", "The tag of the release")
+	_ = viper.BindPFlag("tag", downloadCmd.PersistentFlags().Lookup("tag"))
+
+	downloadCmd.PersistentFlags().StringP("dir", "o", ".", "The directory to write the assets to")
+	_ = viper.BindPFlag("dir", downloadCmd.PersistentFlags().Lookup("dir"))
+
+	downloadCmd.PersistentFlags().BoolP("regex", "", false, "Match asset names with regular expressions instead of glob patterns")
+	_ = viper.BindPFlag("regex", downloadCmd.PersistentFlags().Lookup("regex"))
+
+	downloadCmd.PersistentFlags().BoolP("verify", "", false, "Verify the assets against the checksum manifest of the release")
+	_ = viper.BindPFlag("verify", downloadCmd.PersistentFlags().Lookup("verify"))
+
+	downloadCmd.PersistentFlags().BoolP("verify-signature", "", false, "Verify the signatures of the assets with the trusted keys of the config file")
+	_ = viper.BindPFlag("verify-signature", downloadCmd.PersistentFlags().Lookup("verify-signature"))
+}
@@ -0,0 +1,226 @@
+// Copyright © 2019 xykong <xy.kong@gmail.com>
+
+package cmd
+
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/xykong/github-release/github"
+	"github.com/xykong/github-release/utils"
+)
+
+// editCmd represents the edit command
+var editCmd = &cobra.Command{
+	Use:   "edit",
+	Short: "Change the given fields of a release, keeping the others.",
+	Long: `Change the release given by --id or --tag, draft or not. Only the fields given
+on the command line are sent, the others keep their values:
+
+  github-release edit --tag v0.0.1 --body "New release notes."
+  github-release edit --id 1234 --draft=false
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+
+		bindFlags(cmd, "id", "tag", "body-file", "editor", "template", "plan")
+
+		owner := viper.GetString("user")
+		repo := viper.GetString("repo")
+
+		utils.Verbose("edit called: %v, %s, %s\n", args, owner, repo)
+
+		request := editRequest(cmd)
+		if *request == (github.RequestEditRelease{}) && viper.GetString("body-file") == "" && !viper.GetBool("editor") {
+			return usageErrorf("nothing to change, give at least one field of the release")
+		}
+
+		client := newClient()
+
+		release, err := findEditedRelease(cmd, client, owner, repo)
+		if err != nil {
+			return err
+		}
+
+		t := editTemplater(release, request)
+		if request.Body, err = readBody(cmd, t, "", func() (string, error) { return release.Body, nil }); err != nil {
+			return err
+		}
+		if err := renderEdit(t, request); err != nil {
+			return err
+		}
+
+		if viper.GetBool("plan") {
+			return planEdit(client, owner, repo, release, request)
+		}
+
+		if release, err = client.UpdateRelease(owner, repo, release.Id, request); err != nil {
+			return err
+		}
+
+		utils.Infof(utils.Fields{
+			"id":       release.Id,
+			"tag_name": release.TagName,
+			"url":      release.Url,
+		}, "edit a release success")
+
+		utils.Essential("%d", release.Id)
+		return nil
+	},
+}
+
+// editRequest holds the release fields whose flags were given on the command line.
+func editRequest(cmd *cobra.Command) *github.RequestEditRelease {
+
+	flags := cmd.Flags()
+	request := &github.RequestEditRelease{}
+
+	for name, field := range map[string]**string{
+		"tag_name":         &request.TagName,
+		"target_commitish": &request.TargetCommitish,
+		"name":             &request.Name,
+		"body":             &request.Body,
+	} {
+		if flags.Changed(name) {
+			value, _ := flags.GetString(name)
+			*field = github.String(value)
+		}
+	}
+
+	for name, field := range map[string]**bool{
+		"draft":      &request.Draft,
+		"prerelease": &request.Prerelease,
+	} {
+		if flags.Changed(name) {
+			value, _ := flags.GetBool(name)
+			*field = github.Bool(value)
+		}
+	}
+
+	return request
+}
+
+// findEditedRelease returns the release given by --tag, which may be a draft, or by --id. The id is only
+// taken from the command line, as the default "latest" of show shares its key.
+func findEditedRelease(cmd *cobra.Command, client *github.Client, owner string, repo string) (*github.Release, error) {
+
+	tag := viper.GetString("tag")
+	if tag == "" {
+		if !cmd.Flags().Changed("id") {
+			return nil, usageErrorf("id or tag is required")
+		}
+		id, err := releaseId()
+		if err != nil {
+			return nil, err
+		}
+		return client.GetRelease(owner, repo, id)
+	}
+
+	release, err := client.FindRelease(owner, repo, tag)
+	if err != nil {
+		return nil, err
+	}
+	if release == nil {
+		return nil, fmt.Errorf("no release of tag %s: %w", tag, errNotFound)
+	}
+
+	return release, nil
+}
+
+// editTemplater renders the templates of an edit for the new tag of the release when it is given.
+func editTemplater(release *github.Release, request *github.RequestEditRelease) *templater {
+
+	if request.TagName != nil {
+		return newTemplater(*request.TagName)
+	}
+
+	return newTemplater(release.TagName)
+}
+
+// renderEdit renders the name given as a template. The body is only rendered with --template, by readBody.
+func renderEdit(t *templater, request *github.RequestEditRelease) error {
+
+	if request.Name == nil {
+		return nil
+	}
+
+	name, err := t.render("name", *request.Name)
+	if err != nil {
+		return err
+	}
+	request.Name = &name
+
+	return nil
+}
+
+// planEdit prints the changes the request is about to make to the release.
+func planEdit(client *github.Client, owner string, repo string, release *github.Release, request *github.RequestEditRelease) error {
+
+	p, err := newPlan(client, owner, repo, release)
+	if err != nil {
+		return err
+	}
+
+	planned, _, err := p.planned(editedRelease(release, request), nil, nil)
+	if err != nil {
+		return err
+	}
+
+	p.print(planned)
+	return nil
+}
+
+// editedRelease returns the release as the request is about to change it.
+func editedRelease(release *github.Release, request *github.RequestEditRelease) *github.RequestCreateRelease {
+
+	edited := &github.RequestCreateRelease{
+		TagName:         release.TagName,
+		TargetCommitish: release.TargetCommitish,
+		Name:            release.Name,
+		Body:            release.Body,
+		Draft:           release.Draft,
+		Prerelease:      release.Prerelease,
+	}
+
+	if request.TagName != nil {
+		edited.TagName = *request.TagName
+	}
+	if request.TargetCommitish != nil {
+		edited.TargetCommitish = *request.TargetCommitish
+	}
+	if request.Name != nil {
+		edited.Name = *request.Name
+	}
+	if request.Body != nil {
+		edited.Body = *request.Body
+	}
+	if request.Draft != nil {
+		edited.Draft = *request.Draft
+	}
+	if request.Prerelease != nil {
+		edited.Prerelease = *request.Prerelease
+	}
+
+	return edited
+}
+
+func init() {
+	rootCmd.AddCommand(editCmd)
+
+	editCmd.PersistentFlags().StringP("id", "i", "", "The id of the release")
+	_ = viper.BindPFlag("id", editCmd.PersistentFlags().Lookup("id"))
+
+	editCmd.PersistentFlags().StringP("tag", "", "", "The tag of the release, instead of its id")
+	_ = viper.BindPFlag("tag", editCmd.PersistentFlags().Lookup("tag"))
+
+	// The fields of the release are not bound to viper keys: only those given on the command line are sent.
+	editCmd.PersistentFlags().StringP("tag_name", "", "", "The new tag of the release")
+	editCmd.PersistentFlags().StringP("target_commitish", "", "", "The branch or commit the tag is created from")
+	editCmd.PersistentFlags().StringP("name", "", "", "The name of the release")
+	editCmd.PersistentFlags().StringP("body", "", "", "Text describing the contents of the release")
+	addBodyFlags(editCmd)
+	editCmd.PersistentFlags().BoolP("draft", "", false, "Unpublish the release, --draft=false publishes it")
+	editCmd.PersistentFlags().BoolP("prerelease", "", false, "Identify the release as a prerelease, --prerelease=false as a full release")
+
+	editCmd.PersistentFlags().BoolP("plan", "", false, "Print the changes to the release instead of making them")
+	_ = viper.BindPFlag("plan", editCmd.PersistentFlags().Lookup("plan"))
+}
@@ -0,0 +1,123 @@
+// Copyright © 2019 xykong <xy.kong@gmail.com>
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/xykong/github-release/github"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// Exit codes of github-release, wrapper scripts can branch on them:
+//
+//	0  success
+//	1  any other error
+//	2  usage error: unknown flag, missing or invalid argument
+//	3  authentication error: missing or bad token, insufficient permissions
+//	4  not found: repository, release or asset does not exist
+//	5  conflict: e.g. a release for the tag or an asset with the name already exists
+//	6  rate limited and --no-wait given, or the limit did not reset in time
+//	7  network error: GitHub could not be reached
+const (
+	ExitOK          = 0
+	ExitError       = 1
+	ExitUsage       = 2
+	ExitAuth        = 3
+	ExitNotFound    = 4
+	ExitConflict    = 5
+	ExitRateLimited = 6
+	ExitNetwork     = 7
+)
+
+// errNotFound marks local lookups that found nothing, e.g. no asset matching a pattern.
+var errNotFound = errors.New("not found")
+
+// errConflict marks local checks that found the state of GitHub at odds with the command,
+// e.g. a release that is already published.
+var errConflict = errors.New("conflict")
+
+// usageError marks errors caused by the command line rather than by GitHub.
+type usageError struct {
+	err error
+}
+
+func (e *usageError) Error() string {
+	return e.err.Error()
+}
+
+func (e *usageError) Unwrap() error {
+	return e.err
+}
+
+func usageErrorf(format string, a ...interface{}) error {
+	return &usageError{err: fmt.Errorf(format, a...)}
+}
+
+// usageArgs wraps a cobra argument validator so its errors map to ExitUsage.
+func usageArgs(args cobra.PositionalArgs) cobra.PositionalArgs {
+	return func(cmd *cobra.Command, a []string) error {
+		if err := args(cmd, a); err != nil {
+			return &usageError{err: err}
+		}
+		return nil
+	}
+}
+
+// exitCode maps an error returned by a command to the documented exit codes.
+func exitCode(err error) int {
+
+	if err == nil {
+		return ExitOK
+	}
+
+	var validation *github.ValidationError
+	if errors.As(err, &validation) {
+		if validation.Field == "token" {
+			return ExitAuth
+		}
+		return ExitUsage
+	}
+
+	// cobra has no error type for an unknown command, only its message.
+	var usage *usageError
+	if errors.As(err, &usage) || strings.HasPrefix(err.Error(), "unknown command ") {
+		return ExitUsage
+	}
+
+	if errors.Is(err, errNotFound) {
+		return ExitNotFound
+	}
+
+	if errors.Is(err, errConflict) {
+		return ExitConflict
+	}
+
+	var rate *github.RateLimitError
+	if errors.As(err, &rate) {
+		return ExitRateLimited
+	}
+
+	var api *github.APIError
+	if errors.As(err, &api) {
+		switch {
+		case api.StatusCode == http.StatusUnauthorized || api.StatusCode == http.StatusForbidden:
+			return ExitAuth
+		case api.StatusCode == http.StatusNotFound:
+			return ExitNotFound
+		case api.StatusCode == http.StatusConflict || github.IsAlreadyExists(err):
+			return ExitConflict
+		}
+		return ExitError
+	}
+
+	var network net.Error
+	if errors.As(err, &network) {
+		return ExitNetwork
+	}
+
+	return ExitError
+}
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"github.com/xykong/github-release/github"
+	"net"
+	"net/url"
+	"testing"
+)
+
+func TestExitCode(t *testing.T) {
+
+	apiError := func(status int, errors ...github.FieldError) error {
+		return fmt.Errorf("create a release: %w", &github.APIError{StatusCode: status, Errors: errors})
+	}
+
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"success", nil, ExitOK},
+		{"other", errors.New("boom"), ExitError},
+
+		{"usage", usageErrorf("tag_name is required"), ExitUsage},
+		{"wrapped usage", fmt.Errorf("apply: %w", &usageError{err: errors.New("bad manifest")}), ExitUsage},
+		{"unknown command", errors.New(`unknown command "craete" for "github-release"`), ExitUsage},
+		{"validation", &github.ValidationError{Field: "tag_name"}, ExitUsage},
+		{"missing token", fmt.Errorf("upload: %w", &github.ValidationError{Field: "token"}), ExitAuth},
+
+		{"401", apiError(401), ExitAuth},
+		{"403", apiError(403), ExitAuth},
+		{"404", apiError(404), ExitNotFound},
+		{"409", apiError(409), ExitConflict},
+		{"422 already_exists", apiError(422, github.FieldError{Resource: "Release", Field: "tag_name", Code: "already_exists"}), ExitConflict},
+		{"422 invalid", apiError(422, github.FieldError{Resource: "Release", Field: "tag_name", Code: "invalid"}), ExitError},
+		{"500", apiError(500), ExitError},
+
+		{"local not found", fmt.Errorf("no asset matches *.zip: %w", errNotFound), ExitNotFound},
+		{"local conflict", fmt.Errorf("release v1.0.0 is already published: %w", errConflict), ExitConflict},
+
+		{"rate limited", fmt.Errorf("list: %w", &github.RateLimitError{Secondary: true}), ExitRateLimited},
+		{"network", fmt.Errorf("http.Client.Do failed: %w", &url.Error{Op: "Get", URL: "https://api.github.com",
+			Err: &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}}), ExitNetwork},
+	}
+
+	for _, test := range tests {
+		if code := exitCode(test.err); code != test.want {
+			t.Errorf("%s: exitCode(%v) = %d, want %d", test.name, test.err, code, test.want)
+		}
+	}
+}
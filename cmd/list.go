@@ -4,10 +4,11 @@ package cmd
 
 import (
 	"fmt"
-	"github.com/sirupsen/logrus"
+	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"github.com/xykong/github-release/github"
+	"github.com/xykong/github-release/utils"
 )
 
 // listCmd represents the list command
@@ -17,26 +18,49 @@ var listCmd = &cobra.Command{
 	Long: `Information about published releases are available to everyone. 
 Only users with push access will receive listings for draft releases.
 `,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 
 		_ = viper.BindPFlag("id", cmd.PersistentFlags().Lookup("id"))
 
 		owner := viper.GetString("user")
 		repo := viper.GetString("repo")
 
-		fmt.Printf("list called: %v, %s, %s\n", args, owner, repo)
+		utils.Verbose("list called: %v, %s, %s\n", args, owner, repo)
+
+		client := newClient()
+		opts := &github.ListOptions{
+			PerPage: viper.GetInt("per-page"),
+			Limit:   viper.GetInt("limit"),
+		}
 
 		if viper.GetBool("assets") {
-			github.ListAssets(owner, repo)
-			return
+			id, err := releaseId()
+			if err != nil {
+				return err
+			}
+
+			color.Green("%20s    %10s    %10s    %s\n", "created", "id", "size", "name")
+
+			it := client.Assets(owner, repo, id, opts)
+			for it.Next() {
+				a := it.Asset()
+				fmt.Printf("%20v    %10d    %10d    %s\n",
+					a.CreatedAt.Format("2006-01-02 15:04:05"), a.Id, a.Size, a.Name)
+			}
+
+			return it.Err()
 		}
 
-		_, err := github.ListReleases(owner, repo)
-		if err != nil {
-			logrus.WithFields(logrus.Fields{
-				"error": err,
-			}).Error("list called")
+		color.Green("%20s    %10s    %5s    %s\n", "created", "id", "draft", "tag")
+
+		it := client.Releases(owner, repo, opts)
+		for it.Next() {
+			r := it.Release()
+			fmt.Printf("%20v    %10d    %5v    %s\n",
+				r.CreatedAt.Format("2006-01-02 15:04:05"), r.Id, r.Draft, r.TagName)
 		}
+
+		return it.Err()
 	},
 }
 
@@ -54,6 +78,12 @@ func init() {
 	listCmd.PersistentFlags().StringP("id", "i", "", "The id of the release")
 	_ = viper.BindPFlag("id", listCmd.PersistentFlags().Lookup("id"))
 
+	listCmd.PersistentFlags().IntP("per-page", "", 0, "Results per page (max 100), default: 30")
+	_ = viper.BindPFlag("per-page", listCmd.PersistentFlags().Lookup("per-page"))
+
+	listCmd.PersistentFlags().IntP("limit", "n", 0, "Maximum number of items to list, 0 for all")
+	_ = viper.BindPFlag("limit", listCmd.PersistentFlags().Lookup("limit"))
+
 	// Cobra supports local flags which will only run when this command
 	// is called directly, e.g.:
 	// listCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
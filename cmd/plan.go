@@ -0,0 +1,269 @@
+// Copyright © 2019 xykong <xy.kong@gmail.com>
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"github.com/xykong/github-release/checksum"
+	"github.com/xykong/github-release/github"
+	"github.com/xykong/github-release/sign"
+	"github.com/xykong/github-release/utils"
+	"os"
+	"sort"
+	"strings"
+)
+
+// releaseState is the part of a release that --plan compares.
+type releaseState struct {
+	TagName         string
+	TargetCommitish string
+	Name            string
+	Body            string
+	Draft           bool
+	Prerelease      bool
+	Assets          []assetState
+}
+
+// assetState identifies the content of an asset by its size and, when known, its digest.
+type assetState struct {
+	Name   string
+	Size   int64
+	Digest string
+}
+
+// plan compares the release with the state the command is about to bring it to.
+type plan struct {
+	release *github.Release // The release as it is, nil when it does not exist.
+	current *releaseState
+	// The digests of the assets are only compared with the checksum manifest of the release.
+	algorithm checksum.Algorithm
+	manifest  checksum.Manifest
+}
+
+// newPlan fetches the state of the release, which is nil when there is none yet.
+func newPlan(client *github.Client, owner string, repo string, release *github.Release) (*plan, error) {
+
+	p := &plan{release: release}
+	if release == nil {
+		return p, nil
+	}
+
+	p.current = &releaseState{
+		TagName:         release.TagName,
+		TargetCommitish: release.TargetCommitish,
+		Name:            release.Name,
+		Body:            release.Body,
+		Draft:           release.Draft,
+		Prerelease:      release.Prerelease,
+	}
+
+	assets, err := client.ListAssets(owner, repo, release.Id, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	p.manifest, p.algorithm, err = remoteManifest(client, owner, repo, assets, nil)
+	if err != nil && !errors.Is(err, errNotFound) {
+		return nil, err
+	}
+
+	for _, asset := range assets {
+		p.current.Assets = append(p.current.Assets, assetState{
+			Name:   asset.Name,
+			Size:   asset.Size,
+			Digest: p.manifest[asset.Name],
+		})
+	}
+
+	return p, nil
+}
+
+// findPlan looks the release up by tag, including drafts, and fetches its state.
+func findPlan(client *github.Client, owner string, repo string, tag string) (*plan, error) {
+
+	release, err := client.FindRelease(owner, repo, tag)
+	if err != nil {
+		return nil, err
+	}
+
+	return newPlan(client, owner, repo, release)
+}
+
+// unknownSize is the size of a planned asset that is only known once it is written, e.g. a signature.
+const unknownSize = -1
+
+// planned returns the state of the release after the request and the uploads of the files, with their
+// signatures and the checksum manifest of the options, and the names of the assets deleted and uploaded
+// again. A nil request keeps the release as it is, nil options upload the files only.
+func (p *plan) planned(request *github.RequestCreateRelease, files []*github.RequestUploadAsset,
+	options *uploadOptions) (*releaseState, []string, error) {
+
+	state := &releaseState{}
+	if p.current != nil {
+		*state = *p.current
+		state.Assets = append([]assetState(nil), p.current.Assets...)
+	}
+
+	if request != nil {
+		state.TagName = request.TagName
+		state.TargetCommitish = request.TargetCommitish
+		state.Name = request.Name
+		state.Body = request.Body
+		state.Draft = request.Draft
+		state.Prerelease = request.Prerelease
+	}
+
+	if options == nil {
+		options = &uploadOptions{}
+	}
+
+	var uploads []assetState
+	manifest := checksum.Manifest{}
+	for _, file := range files {
+
+		info, err := os.Stat(file.Filename)
+		if err != nil {
+			return nil, nil, &usageError{err: err}
+		}
+
+		asset := assetState{Name: assetName(file), Size: info.Size()}
+		if p.algorithm != "" {
+			if asset.Digest, err = checksum.File(file.Filename, p.algorithm); err != nil {
+				return nil, nil, err
+			}
+		}
+
+		if options.algorithm != "" {
+			digest := asset.Digest
+			if options.algorithm != p.algorithm {
+				if digest, err = checksum.File(file.Filename, options.algorithm); err != nil {
+					return nil, nil, err
+				}
+			}
+			manifest[asset.Name] = digest
+		}
+
+		uploads = append(uploads, asset)
+		uploads = append(uploads, signatureStates(asset.Name, options.signers)...)
+	}
+
+	if len(manifest) > 0 {
+		// The manifest is merged into the one of the release, whose entries are only known when the plan
+		// compares the checksums of the same algorithm.
+		asset := assetState{Name: options.algorithm.ManifestName(), Size: unknownSize}
+		switch {
+		case options.algorithm == p.algorithm:
+			merged := checksum.Manifest{}
+			merged.Merge(p.manifest)
+			merged.Merge(manifest)
+			asset.Size = int64(len(merged.Bytes()))
+		case state.asset(asset.Name) == nil:
+			asset.Size = int64(len(manifest.Bytes()))
+		}
+
+		uploads = append(uploads, asset)
+		uploads = append(uploads, signatureStates(asset.Name, options.signers)...)
+	}
+
+	var replaced []string
+	for _, asset := range uploads {
+		if existing := state.asset(asset.Name); existing != nil {
+			*existing = asset
+			replaced = append(replaced, asset.Name)
+			continue
+		}
+		state.Assets = append(state.Assets, asset)
+	}
+
+	return state, replaced, nil
+}
+
+// asset returns the asset of the state with the name, or nil.
+func (s *releaseState) asset(name string) *assetState {
+
+	for i := range s.Assets {
+		if s.Assets[i].Name == name {
+			return &s.Assets[i]
+		}
+	}
+
+	return nil
+}
+
+// signatureStates returns the signatures of the asset with the name by the signers, of unknown size.
+func signatureStates(name string, signers []sign.Signer) []assetState {
+
+	var states []assetState
+	for _, signer := range signers {
+		states = append(states, assetState{Name: name + signer.Extension(), Size: unknownSize})
+	}
+
+	return states
+}
+
+// print writes the plan as a diff of the release as it is and as it is going to be.
+func (p *plan) print(planned *releaseState) {
+
+	from := "release (none)"
+	if p.release != nil {
+		from = fmt.Sprintf("release %s (id %d)", p.release.TagName, p.release.Id)
+	}
+
+	a := p.current.lines(p.algorithm)
+	b := planned.lines(p.algorithm)
+	utils.PrintDiff(from, "release "+planned.TagName+" (planned)", a, b)
+
+	if p.current != nil && p.algorithm == "" {
+		fmt.Println("# asset checksums are not compared, the release has no checksum manifest")
+	}
+
+	if !utils.Changed(utils.Diff(a, b)) {
+		fmt.Println("# no changes")
+	}
+}
+
+// lines renders the state one field per line, the body indented below its key.
+func (s *releaseState) lines(algorithm checksum.Algorithm) []string {
+
+	if s == nil {
+		return nil
+	}
+
+	lines := []string{
+		"tag_name: " + s.TagName,
+		"target_commitish: " + s.TargetCommitish,
+		"name: " + s.Name,
+		fmt.Sprintf("draft: %t", s.Draft),
+		fmt.Sprintf("prerelease: %t", s.Prerelease),
+		"body:",
+	}
+
+	if s.Body != "" {
+		for _, line := range strings.Split(strings.TrimRight(s.Body, "\n"), "\n") {
+			lines = append(lines, "  "+strings.TrimRight(line, "\r"))
+		}
+	}
+
+	lines = append(lines, "assets:")
+
+	assets := append([]assetState(nil), s.Assets...)
+	sort.Slice(assets, func(i, j int) bool { return assets[i].Name < assets[j].Name })
+	for _, asset := range assets {
+		size := "size unknown"
+		if asset.Size != unknownSize {
+			size = utils.FormatBytes(asset.Size)
+		}
+		line := fmt.Sprintf("  %s  %s", asset.Name, size)
+		if algorithm != "" {
+			digest := asset.Digest
+			if digest == "" {
+				digest = "unknown"
+			}
+			line += fmt.Sprintf("  %s:%s", algorithm, digest)
+		}
+		lines = append(lines, line)
+	}
+
+	return lines
+}
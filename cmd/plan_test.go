@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/xykong/github-release/checksum"
+	"github.com/xykong/github-release/sign"
+	"io"
+	"strings"
+	"testing"
+)
+
+// extensionSigner is a signer that only names its signatures.
+type extensionSigner string
+
+func (s extensionSigner) Extension() string {
+	return string(s)
+}
+
+func (s extensionSigner) Sign(io.Reader, io.Writer) error {
+	return nil
+}
+
+func TestPlanned(t *testing.T) {
+
+	files, cleanup := writeFiles(t, "a.bin")
+	defer cleanup()
+
+	sha256, err := checksum.File(files[0].Filename, checksum.SHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sha512, err := checksum.File(files[0].Filename, checksum.SHA512)
+	if err != nil {
+		t.Fatal(err)
+	}
+	size := int64(len("contents of a.bin"))
+	manifestSize := func(manifest checksum.Manifest) int64 { return int64(len(manifest.Bytes())) }
+
+	existing := func() *plan {
+		return &plan{
+			current: &releaseState{Assets: []assetState{
+				{Name: "a.bin", Size: 3, Digest: "aa"},
+				{Name: "b.bin", Size: 4, Digest: "bb"},
+				{Name: "SHA256SUMS", Size: 142},
+			}},
+			algorithm: checksum.SHA256,
+			manifest:  checksum.Manifest{"a.bin": "aa", "b.bin": "bb"},
+		}
+	}
+
+	tests := []struct {
+		name         string
+		plan         *plan
+		options      *uploadOptions
+		wantAssets   []assetState
+		wantReplaced []string
+	}{
+		{
+			name:       "files only",
+			plan:       &plan{},
+			wantAssets: []assetState{{Name: "a.bin", Size: size}},
+		},
+		{
+			name:    "new release with a manifest and signatures",
+			plan:    &plan{},
+			options: &uploadOptions{algorithm: checksum.SHA512, signers: []sign.Signer{extensionSigner(".asc"), extensionSigner(".sig")}},
+			wantAssets: []assetState{
+				{Name: "a.bin", Size: size},
+				{Name: "a.bin.asc", Size: unknownSize},
+				{Name: "a.bin.sig", Size: unknownSize},
+				{Name: "SHA512SUMS", Size: manifestSize(checksum.Manifest{"a.bin": sha512})},
+				{Name: "SHA512SUMS.asc", Size: unknownSize},
+				{Name: "SHA512SUMS.sig", Size: unknownSize},
+			},
+		},
+		{
+			name:    "manifest merged into the one of the release",
+			plan:    existing(),
+			options: &uploadOptions{algorithm: checksum.SHA256},
+			wantAssets: []assetState{
+				{Name: "a.bin", Size: size, Digest: sha256},
+				{Name: "b.bin", Size: 4, Digest: "bb"},
+				{Name: "SHA256SUMS", Size: manifestSize(checksum.Manifest{"a.bin": sha256, "b.bin": "bb"})},
+			},
+			wantReplaced: []string{"a.bin", "SHA256SUMS"},
+		},
+		{
+			name:    "manifest of another algorithm",
+			plan:    existing(),
+			options: &uploadOptions{algorithm: checksum.SHA512, signers: []sign.Signer{extensionSigner(".sig")}},
+			wantAssets: []assetState{
+				{Name: "a.bin", Size: size, Digest: sha256},
+				{Name: "b.bin", Size: 4, Digest: "bb"},
+				{Name: "SHA256SUMS", Size: 142},
+				{Name: "a.bin.sig", Size: unknownSize},
+				{Name: "SHA512SUMS", Size: manifestSize(checksum.Manifest{"a.bin": sha512})},
+				{Name: "SHA512SUMS.sig", Size: unknownSize},
+			},
+			wantReplaced: []string{"a.bin"},
+		},
+		{
+			// The release has a SHA512SUMS too, so the entries of its SHA256SUMS are not known.
+			name: "manifest of unknown entries",
+			plan: &plan{
+				current:   &releaseState{Assets: []assetState{{Name: "SHA256SUMS", Size: 100}, {Name: "SHA512SUMS", Size: 200}}},
+				algorithm: checksum.SHA512,
+				manifest:  checksum.Manifest{},
+			},
+			options: &uploadOptions{algorithm: checksum.SHA256},
+			wantAssets: []assetState{
+				{Name: "SHA256SUMS", Size: unknownSize},
+				{Name: "SHA512SUMS", Size: 200},
+				{Name: "a.bin", Size: size, Digest: sha512},
+			},
+			wantReplaced: []string{"SHA256SUMS"},
+		},
+	}
+
+	for _, test := range tests {
+
+		state, replaced, err := test.plan.planned(nil, files, test.options)
+		if err != nil {
+			t.Errorf("%s: planned = %v", test.name, err)
+			continue
+		}
+
+		if got, want := fmt.Sprint(state.Assets), fmt.Sprint(test.wantAssets); got != want {
+			t.Errorf("%s: assets\n%s\nwant\n%s", test.name, got, want)
+		}
+		if got, want := strings.Join(replaced, ","), strings.Join(test.wantReplaced, ","); got != want {
+			t.Errorf("%s: replaced %q, want %q", test.name, got, want)
+		}
+	}
+}
+
+func TestLinesUnknownSize(t *testing.T) {
+
+	s := &releaseState{Assets: []assetState{{Name: "a.bin.sig", Size: unknownSize}, {Name: "a.bin", Size: 3}}}
+
+	got := strings.Join(s.lines(checksum.SHA256), "\n")
+	want := "  a.bin  3 B  sha256:unknown\n  a.bin.sig  size unknown  sha256:unknown"
+	if !strings.HasSuffix(got, want) {
+		t.Errorf("lines\n%s\nwant the assets\n%s", got, want)
+	}
+}
@@ -0,0 +1,276 @@
+// Copyright © 2019 xykong <xy.kong@gmail.com>
+
+package cmd
+
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/xykong/github-release/github"
+	"github.com/xykong/github-release/utils"
+	"os"
+)
+
+// releaseCmd represents the release command
+var releaseCmd = &cobra.Command{
+	Use:   "release [file...]",
+	Short: "Create a release, upload its assets and publish it.",
+	Long: `Create the release of --tag_name as a draft, or reuse the draft of the tag,
+upload the files to it and publish it once all uploads succeeded.
+
+When an upload fails the draft created by the command is deleted again, so a
+release is never published with a part of its assets. A draft that existed
+before is kept, as is the draft with --keep-draft. A release of the tag that
+is already published is not changed.
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+
+		bindFlags(cmd, "tag_name", "target_commitish", "name", "body", "body-file", "editor", "template", "notes-from-git",
+			"prerelease", "asset-name", "label", "parallel", "keep-going", "checksum", "sign", "keep-draft", "plan")
+
+		owner := viper.GetString("user")
+		repo := viper.GetString("repo")
+		tag := viper.GetString("tag_name")
+
+		utils.Verbose("release called: %v, %s, %s\n", args, owner, repo)
+
+		if tag == "" {
+			return usageErrorf("tag_name is required")
+		}
+
+		for _, name := range args {
+			if _, err := os.Stat(name); err != nil {
+				return &usageError{err: err}
+			}
+		}
+
+		request := &github.RequestCreateRelease{
+			TagName:         tag,
+			TargetCommitish: viper.GetString("target_commitish"),
+			Name:            viper.GetString("name"),
+			Body:            viper.GetString("body"),
+			Prerelease:      viper.GetBool("prerelease"),
+		}
+
+		client := newClient()
+
+		files := fileRequests(args, viper.GetString("asset-name"), viper.GetString("label"))
+
+		// The assets are rendered first, so the body can list their names.
+		t := newTemplater(tag)
+		if err := t.renderAssets(files); err != nil {
+			return err
+		}
+
+		body, err := readBody(cmd, t, request.Body, func() (string, error) {
+			release, err := client.FindRelease(owner, repo, tag)
+			if err != nil || release == nil {
+				return request.Body, err
+			}
+			return release.Body, nil
+		})
+		if err != nil {
+			return err
+		}
+		if body != nil {
+			request.Body = *body
+		}
+
+		if err := t.renderRelease(request); err != nil {
+			return err
+		}
+
+		// A reused draft keeps the fields not given on the command line, and is published.
+		edit := editRequest(cmd)
+		edit.TagName = nil
+		edit.Body = body
+		if edit.Name != nil {
+			edit.Name = &request.Name
+		}
+		edit.Draft = github.Bool(false)
+
+		options, err := newUploadOptions()
+		if err != nil {
+			return err
+		}
+
+		if viper.GetBool("plan") {
+			return planShip(client, owner, repo, request, edit, files, options, false)
+		}
+
+		release, err := shipRelease(client, owner, repo, request, edit, files, options, false)
+		if err != nil {
+			return err
+		}
+
+		utils.Essential("%d", release.Id)
+		return nil
+	},
+	Example: `github-release release --tag_name v0.0.1 --name "v0.0.1" --checksum sha256 dist/*.tar.gz`,
+}
+
+// shipRelease brings the release of the tag to the state of the request, uploading the files to it first.
+// A missing release is created as a draft and published, unless request.Draft is set, once all uploads
+// succeeded; when an upload fails the draft is deleted again unless --keep-draft is given. An existing
+// release is changed in the fields of edit, with update even a published one, otherwise only a draft is
+// reused; the assets of a reused release are replaced.
+func shipRelease(client *github.Client, owner string, repo string, request *github.RequestCreateRelease,
+	edit *github.RequestEditRelease, files []*github.RequestUploadAsset, options *uploadOptions,
+	update bool) (*github.Release, error) {
+
+	release, err := client.FindRelease(owner, repo, request.TagName)
+	if err != nil {
+		return nil, err
+	}
+
+	var created bool
+	switch {
+	case release == nil:
+		draft := *request
+		draft.Draft = true
+		if release, err = client.CreateRelease(owner, repo, &draft); err != nil {
+			return nil, err
+		}
+		created = true
+
+		utils.Infof(utils.Fields{
+			"id":       release.Id,
+			"tag_name": release.TagName,
+		}, "create a draft release success")
+
+	case release.Draft || update:
+		utils.Infof(utils.Fields{
+			"id":       release.Id,
+			"tag_name": release.TagName,
+		}, "reuse the release")
+
+	default:
+		return nil, fmt.Errorf("release %s is already published: %w", request.TagName, errConflict)
+	}
+
+	// A new draft has the fields of the request already and is only published.
+	final := edit
+	if created {
+		final = &github.RequestEditRelease{}
+		if !request.Draft {
+			final.Draft = github.Bool(false)
+		}
+	}
+	pending := *final != github.RequestEditRelease{}
+
+	// A published release is edited before its assets are replaced, a draft is published after the uploads.
+	if pending && !release.Draft {
+		if release, err = client.UpdateRelease(owner, repo, release.Id, final); err != nil {
+			return nil, err
+		}
+		pending = false
+	}
+
+	// The assets of a reused release, e.g. a draft left by a failed run, are replaced.
+	err = options.upload(client, owner, repo, release, files, !created)
+
+	shipped := release
+	if err == nil && pending {
+		shipped, err = client.UpdateRelease(owner, repo, release.Id, final)
+	}
+
+	if err != nil {
+		if created && !viper.GetBool("keep-draft") {
+			if deleteErr := client.DeleteRelease(owner, repo, release.Id); deleteErr != nil {
+				return nil, fmt.Errorf("%w, and the draft release %d is left: %v", err, release.Id, deleteErr)
+			}
+
+			utils.Infof(utils.Fields{
+				"id": release.Id,
+			}, "delete the draft release")
+		}
+		return nil, err
+	}
+
+	utils.Infof(utils.Fields{
+		"id":       shipped.Id,
+		"tag_name": shipped.TagName,
+		"draft":    shipped.Draft,
+		"url":      shipped.HtmlUrl,
+	}, "ship a release success")
+
+	return shipped, nil
+}
+
+// planShip prints the changes shipRelease is about to make to the release.
+func planShip(client *github.Client, owner string, repo string, request *github.RequestCreateRelease,
+	edit *github.RequestEditRelease, files []*github.RequestUploadAsset, options *uploadOptions, update bool) error {
+
+	p, err := findPlan(client, owner, repo, request.TagName)
+	if err != nil {
+		return err
+	}
+
+	final := request
+	if p.release != nil {
+		final = editedRelease(p.release, edit)
+	}
+
+	planned, replaced, err := p.planned(final, files, options)
+	if err != nil {
+		return err
+	}
+
+	p.print(planned)
+
+	for _, name := range replaced {
+		fmt.Printf("# the asset %s is uploaded again and replaces the existing one\n", name)
+	}
+
+	if p.release != nil && !p.release.Draft && !update {
+		fmt.Printf("# release %s is already published, the command fails\n", request.TagName)
+	}
+
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(releaseCmd)
+
+	releaseCmd.PersistentFlags().StringP("tag_name", "", "", "The tag of the release")
+	_ = viper.BindPFlag("tag_name", releaseCmd.PersistentFlags().Lookup("tag_name"))
+
+	releaseCmd.PersistentFlags().StringP("target_commitish", "", "", "The branch or commit the tag is created from, default: the default branch")
+	_ = viper.BindPFlag("target_commitish", releaseCmd.PersistentFlags().Lookup("target_commitish"))
+
+	releaseCmd.PersistentFlags().StringP("name", "", "", "The name of the release")
+	_ = viper.BindPFlag("name", releaseCmd.PersistentFlags().Lookup("name"))
+
+	releaseCmd.PersistentFlags().StringP("body", "", "", "Text describing the contents of the release")
+	_ = viper.BindPFlag("body", releaseCmd.PersistentFlags().Lookup("body"))
+
+	addBodyFlags(releaseCmd)
+	addNotesFlag(releaseCmd)
+
+	releaseCmd.PersistentFlags().BoolP("prerelease", "", false, "Identify the release as a prerelease")
+	_ = viper.BindPFlag("prerelease", releaseCmd.PersistentFlags().Lookup("prerelease"))
+
+	releaseCmd.PersistentFlags().StringP("asset-name", "", "", "The name of the assets, a template, default: the base name of the file")
+	_ = viper.BindPFlag("asset-name", releaseCmd.PersistentFlags().Lookup("asset-name"))
+
+	releaseCmd.PersistentFlags().StringP("label", "l", "", "The label of the assets")
+	_ = viper.BindPFlag("label", releaseCmd.PersistentFlags().Lookup("label"))
+
+	releaseCmd.PersistentFlags().IntP("parallel", "p", 1, "Number of files uploaded at the same time")
+	_ = viper.BindPFlag("parallel", releaseCmd.PersistentFlags().Lookup("parallel"))
+
+	releaseCmd.PersistentFlags().BoolP("keep-going", "k", false, "Continue uploading the remaining files after a failure")
+	_ = viper.BindPFlag("keep-going", releaseCmd.PersistentFlags().Lookup("keep-going"))
+
+	releaseCmd.PersistentFlags().StringP("checksum", "", "", "Publish a SHA256SUMS or SHA512SUMS manifest of the files: sha256 or sha512")
+	_ = viper.BindPFlag("checksum", releaseCmd.PersistentFlags().Lookup("checksum"))
+
+	releaseCmd.PersistentFlags().BoolP("sign", "", false, "Upload detached signatures (.asc, .sig) of the files and the checksum manifest, with the keys of the config file")
+	_ = viper.BindPFlag("sign", releaseCmd.PersistentFlags().Lookup("sign"))
+
+	releaseCmd.PersistentFlags().BoolP("keep-draft", "", false, "Keep the draft release when an upload fails")
+	_ = viper.BindPFlag("keep-draft", releaseCmd.PersistentFlags().Lookup("keep-draft"))
+
+	releaseCmd.PersistentFlags().BoolP("plan", "", false, "Print the changes to the release instead of making them")
+	_ = viper.BindPFlag("plan", releaseCmd.PersistentFlags().Lookup("plan"))
+}
@@ -0,0 +1,250 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/xykong/github-release/github"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeGitHub serves the release endpoints of repository o/r with at most one release, and records the
+// requests that change it.
+type fakeGitHub struct {
+	*httptest.Server
+
+	mu      sync.Mutex
+	release *github.Release
+	assets  []github.Asset
+	changes []string // "METHOD path body" of every request but GET.
+	nextId  int64
+}
+
+func newFakeGitHub(release *github.Release, assets ...github.Asset) *fakeGitHub {
+
+	f := &fakeGitHub{release: release, assets: assets, nextId: 100}
+	f.Server = httptest.NewServer(http.HandlerFunc(f.serve))
+	if release != nil {
+		release.UploadUrl = f.URL + "/api/uploads/repos/o/r/releases/1/assets{?name,label}"
+	}
+
+	return f
+}
+
+func (f *fakeGitHub) client() *github.Client {
+	return github.NewClient(github.Options{BaseURL: f.URL, Token: "token"})
+}
+
+func (f *fakeGitHub) serve(w http.ResponseWriter, r *http.Request) {
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	body, _ := ioutil.ReadAll(r.Body)
+	path := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, "/api/v3"), "/api/uploads")
+	if r.Method != http.MethodGet {
+		if r.URL.Query().Get("name") != "" {
+			body = []byte(r.URL.Query().Get("name")) // Uploads are recorded by name.
+		}
+		f.changes = append(f.changes, strings.TrimSpace(r.Method+" "+path+" "+string(body)))
+	}
+
+	reply := func(status int, v interface{}) {
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(v)
+	}
+	notFound := func() { reply(http.StatusNotFound, map[string]string{"message": "Not Found"}) }
+
+	switch {
+	case r.Method == http.MethodGet && strings.HasPrefix(path, "/repos/o/r/releases/tags/"):
+		if f.release == nil || f.release.Draft || f.release.TagName != strings.TrimPrefix(path, "/repos/o/r/releases/tags/") {
+			notFound()
+			return
+		}
+		reply(http.StatusOK, f.release)
+
+	case r.Method == http.MethodGet && path == "/repos/o/r/releases":
+		releases := []*github.Release{}
+		if f.release != nil {
+			releases = append(releases, f.release)
+		}
+		reply(http.StatusOK, releases)
+
+	case r.Method == http.MethodPost && path == "/repos/o/r/releases":
+		f.release = &github.Release{Id: 1, UploadUrl: f.URL + "/api/uploads/repos/o/r/releases/1/assets{?name,label}"}
+		_ = json.Unmarshal(body, f.release)
+		reply(http.StatusCreated, f.release)
+
+	case r.Method == http.MethodPatch && path == "/repos/o/r/releases/1":
+		_ = json.Unmarshal(body, f.release)
+		reply(http.StatusOK, f.release)
+
+	case r.Method == http.MethodGet && path == "/repos/o/r/releases/1/assets":
+		reply(http.StatusOK, append([]github.Asset{}, f.assets...))
+
+	case r.Method == http.MethodPost && path == "/repos/o/r/releases/1/assets":
+		name := r.URL.Query().Get("name")
+		for _, asset := range f.assets {
+			if asset.Name == name {
+				reply(http.StatusUnprocessableEntity, map[string]interface{}{"message": "Validation Failed",
+					"errors": []map[string]string{{"resource": "ReleaseAsset", "code": "already_exists", "field": "name"}}})
+				return
+			}
+		}
+		f.nextId++
+		asset := github.Asset{Id: f.nextId, Name: name, Size: int64(r.ContentLength), State: "uploaded"}
+		f.assets = append(f.assets, asset)
+		reply(http.StatusCreated, asset)
+
+	case strings.HasPrefix(path, "/repos/o/r/releases/assets/"):
+		id, _ := strconv.ParseInt(strings.TrimPrefix(path, "/repos/o/r/releases/assets/"), 10, 64)
+		for i := range f.assets {
+			if f.assets[i].Id != id {
+				continue
+			}
+			switch r.Method {
+			case http.MethodDelete:
+				f.assets = append(f.assets[:i], f.assets[i+1:]...)
+				w.WriteHeader(http.StatusNoContent)
+			case http.MethodPatch:
+				_ = json.Unmarshal(body, &f.assets[i])
+				reply(http.StatusOK, f.assets[i])
+			default:
+				reply(http.StatusOK, f.assets[i])
+			}
+			return
+		}
+		notFound()
+
+	default:
+		notFound()
+	}
+}
+
+// writeFiles writes files with the names to a new directory and returns their upload requests.
+func writeFiles(t *testing.T, names ...string) ([]*github.RequestUploadAsset, func()) {
+
+	dir, err := ioutil.TempDir("", "release")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var paths []string
+	for _, name := range names {
+		path := filepath.Join(dir, name)
+		if err := ioutil.WriteFile(path, []byte("contents of "+name), 0644); err != nil {
+			t.Fatal(err)
+		}
+		paths = append(paths, path)
+	}
+
+	return fileRequests(paths, "", ""), func() { _ = os.RemoveAll(dir) }
+}
+
+func TestShipRelease(t *testing.T) {
+
+	draft := func() *github.Release {
+		return &github.Release{Id: 1, TagName: "v5.0.0", Name: "Five", Body: "notes", Draft: true, Prerelease: true}
+	}
+
+	tests := []struct {
+		name        string
+		release     *github.Release
+		edit        *github.RequestEditRelease
+		update      bool
+		wantChanges []string
+		wantErr     error
+		wantRelease github.Release
+	}{
+		{
+			name: "new release",
+			edit: &github.RequestEditRelease{Draft: github.Bool(false)},
+			wantChanges: []string{
+				`POST /repos/o/r/releases {"tag_name":"v5.0.0","target_commitish":"","name":"","body":"","draft":true,"prerelease":false}`,
+				"POST /repos/o/r/releases/1/assets a.bin",
+				`PATCH /repos/o/r/releases/1 {"draft":false}`,
+			},
+			wantRelease: github.Release{TagName: "v5.0.0"},
+		},
+		{
+			name:    "reused draft keeps its fields",
+			release: draft(),
+			edit:    &github.RequestEditRelease{Draft: github.Bool(false)},
+			wantChanges: []string{
+				"POST /repos/o/r/releases/1/assets a.bin",
+				`PATCH /repos/o/r/releases/1 {"draft":false}`,
+			},
+			wantRelease: github.Release{TagName: "v5.0.0", Name: "Five", Body: "notes", Prerelease: true},
+		},
+		{
+			name:    "reused draft with the fields given",
+			release: draft(),
+			edit:    &github.RequestEditRelease{Name: github.String("V"), Prerelease: github.Bool(false), Draft: github.Bool(false)},
+			wantChanges: []string{
+				"POST /repos/o/r/releases/1/assets a.bin",
+				`PATCH /repos/o/r/releases/1 {"name":"V","draft":false,"prerelease":false}`,
+			},
+			wantRelease: github.Release{TagName: "v5.0.0", Name: "V", Body: "notes"},
+		},
+		{
+			name:    "published release",
+			release: &github.Release{Id: 1, TagName: "v5.0.0"},
+			edit:    &github.RequestEditRelease{Draft: github.Bool(false)},
+			wantErr: errConflict,
+		},
+		{
+			name:    "published release updated before the uploads",
+			release: &github.Release{Id: 1, TagName: "v5.0.0", Name: "Five", Body: "notes"},
+			edit:    manifestEdit(&github.RequestCreateRelease{TagName: "v5.0.0", Body: "new notes", Prerelease: true}),
+			update:  true,
+			wantChanges: []string{
+				`PATCH /repos/o/r/releases/1 {"body":"new notes","draft":false,"prerelease":true}`,
+				"POST /repos/o/r/releases/1/assets a.bin",
+			},
+			wantRelease: github.Release{TagName: "v5.0.0", Name: "Five", Body: "new notes", Prerelease: true},
+		},
+	}
+
+	for _, test := range tests {
+
+		f := newFakeGitHub(test.release)
+		files, cleanup := writeFiles(t, "a.bin")
+
+		request := &github.RequestCreateRelease{TagName: "v5.0.0"}
+		_, err := shipRelease(f.client(), "o", "r", request, test.edit, files, &uploadOptions{parallel: 1}, test.update)
+		f.Close()
+		cleanup()
+
+		if test.wantErr != nil {
+			if !errors.Is(err, test.wantErr) {
+				t.Errorf("%s: shipRelease = %v, want %v", test.name, err, test.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: shipRelease = %v", test.name, err)
+			continue
+		}
+
+		if got, want := strings.Join(f.changes, "\n"), strings.Join(test.wantChanges, "\n"); got != want {
+			t.Errorf("%s: requests\n%s\nwant\n%s", test.name, got, want)
+		}
+
+		if got, want := describe(*f.release), describe(test.wantRelease); got != want {
+			t.Errorf("%s: release %s, want %s", test.name, got, want)
+		}
+	}
+}
+
+// describe returns the fields of the release that shipRelease sets.
+func describe(r github.Release) string {
+	return fmt.Sprintf("%s name %q body %q draft %v prerelease %v", r.TagName, r.Name, r.Body, r.Draft, r.Prerelease)
+}
@@ -5,10 +5,14 @@ package cmd
 import (
 	"fmt"
 	"github.com/sirupsen/logrus"
+	"github.com/xykong/github-release/git"
+	"github.com/xykong/github-release/github"
 	"github.com/xykong/github-release/utils"
 	"os"
+	"strconv"
 
 	"github.com/mitchellh/go-homedir"
+	"github.com/spf13/cast"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -32,16 +36,24 @@ to quickly create a Cobra application.`,
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
+// Failures exit with one of the codes documented in exit.go.
 func Execute() {
 	if err := rootCmd.Execute(); err != nil {
-		fmt.Println(err)
-		os.Exit(1)
+		utils.Error("%v", err)
+		os.Exit(exitCode(err))
 	}
 }
 
 func init() {
 	cobra.OnInitialize(initConfig)
 
+	// Errors are printed by Execute, which also picks the exit code.
+	rootCmd.SilenceErrors = true
+	rootCmd.SilenceUsage = true
+	rootCmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		return &usageError{err: err}
+	})
+
 	// Here you will define your flags and configuration settings.
 	// Cobra supports persistent flags, which, if defined here,
 	// will be global for your application.
@@ -53,6 +65,18 @@ func init() {
 	rootCmd.PersistentFlags().StringP("repo", "r", "", "The name of the repository")
 	_ = viper.BindPFlag("repo", rootCmd.PersistentFlags().Lookup("repo"))
 
+	rootCmd.PersistentFlags().StringP("remote", "", "origin", "The git remote of the working directory that user and repo default to")
+	_ = viper.BindPFlag("remote", rootCmd.PersistentFlags().Lookup("remote"))
+
+	rootCmd.PersistentFlags().StringP("token", "", "", "The OAuth token, required for private repositories and to change releases")
+	_ = viper.BindPFlag("token", rootCmd.PersistentFlags().Lookup("token"))
+
+	rootCmd.PersistentFlags().StringP("github", "", "", "The API endpoint, e.g. https://github.example.com/api/v3 for GitHub Enterprise (default https://api.github.com)")
+	_ = viper.BindPFlag("github", rootCmd.PersistentFlags().Lookup("github"))
+
+	rootCmd.PersistentFlags().StringP("uploads", "", "", "The upload endpoint, default: the upload_url of the release")
+	_ = viper.BindPFlag("uploads", rootCmd.PersistentFlags().Lookup("uploads"))
+
 	rootCmd.PersistentFlags().BoolP("debug", "d", false, "Verbose message for debug")
 	_ = viper.BindPFlag("debug", rootCmd.PersistentFlags().Lookup("debug"))
 
@@ -61,6 +85,20 @@ func init() {
 
 	rootCmd.PersistentFlags().BoolP("essential", "s", false, "Verbose message for debug")
 	_ = viper.BindPFlag("essential", rootCmd.PersistentFlags().Lookup("essential"))
+
+	rootCmd.PersistentFlags().BoolP("no-wait", "", false, "Fail immediately instead of waiting when rate limited")
+	_ = viper.BindPFlag("no-wait", rootCmd.PersistentFlags().Lookup("no-wait"))
+
+	rootCmd.PersistentFlags().BoolP("dry-run", "", false, "Print the requests that change GitHub instead of sending them, read-only requests are still sent")
+	_ = viper.BindPFlag("dry-run", rootCmd.PersistentFlags().Lookup("dry-run"))
+
+	// The remaining retry settings (retry.max_delay, retry.jitter, retry.status_codes
+	// and retry.methods) are only read from the config file.
+	rootCmd.PersistentFlags().IntP("retry", "", github.DefaultRetryPolicy.MaxAttempts, "Attempts for transient failures, 1 or less disables retries")
+	_ = viper.BindPFlag("retry.max_attempts", rootCmd.PersistentFlags().Lookup("retry"))
+
+	rootCmd.PersistentFlags().DurationP("retry-delay", "", 0, "Delay before the first retry, doubled on each attempt (default 1s)")
+	_ = viper.BindPFlag("retry.base_delay", rootCmd.PersistentFlags().Lookup("retry-delay"))
 }
 
 // initConfig reads in config file and ENV variables if set.
@@ -84,19 +122,22 @@ func initConfig() {
 		viper.SetConfigName(".github-release")
 	}
 
-	viper.SetDefault("github", "https://api.github.com")
+	viper.SetDefault("github", github.DefaultBaseURL)
 
 	viper.AutomaticEnv() // read in environment variables that match
 
 	// If a config file is found, read it in.
 	if err := viper.ReadInConfig(); err == nil {
-		utils.Verbose("Using config file:", viper.ConfigFileUsed())
+		utils.Verbose("Using config file: %s", viper.ConfigFileUsed())
 	}
 
 	level := logrus.InfoLevel
-	if viper.GetBool("verbose") {
+	if viper.GetBool("verbose") || viper.GetBool("debug") {
 		level = logrus.DebugLevel
 	}
+	if viper.GetBool("essential") {
+		level = logrus.WarnLevel
+	}
 	logrus.SetLevel(level)
 	logrus.SetOutput(os.Stdout)
 	logrus.SetFormatter(&logrus.TextFormatter{
@@ -104,4 +145,108 @@ func initConfig() {
 		DisableTimestamp:       true,
 		FullTimestamp:          true,
 	})
+
+	detectRepository()
+}
+
+// detectRepository fills the user and repo missing from the flags, environment and config file with the
+// owner and name of the repository of the git remote, when the working directory is in a clone.
+func detectRepository() {
+
+	if viper.GetString("user") != "" && viper.GetString("repo") != "" {
+		return
+	}
+
+	remote, err := git.FindRemote(viper.GetString("remote"))
+	if err != nil {
+		utils.Verbose("No repository detected: %v", err)
+		return
+	}
+
+	if viper.GetString("user") == "" {
+		viper.Set("user", remote.Owner)
+	}
+	if viper.GetString("repo") == "" {
+		viper.Set("repo", remote.Repo)
+	}
+
+	utils.Verbose("Using repository %s/%s of remote %s: %s", viper.GetString("user"), viper.GetString("repo"),
+		remote.Name, remote.URL)
+}
+
+// newClient builds a github client from the flags, environment and config file.
+func newClient() *github.Client {
+
+	// A jitter of 0 is kept, only a missing one falls back to the default.
+	var jitter *float64
+	if viper.IsSet("retry.jitter") {
+		jitter = github.Float64(viper.GetFloat64("retry.jitter"))
+	}
+
+	// RetryPolicy takes 0 for the default, here it means a single attempt as 1 does.
+	attempts := viper.GetInt("retry.max_attempts")
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	return github.NewClient(github.Options{
+		BaseURL:   viper.GetString("github"),
+		UploadURL: viper.GetString("uploads"),
+		Token:     viper.GetString("token"),
+		Logger:    logrus.StandardLogger(),
+		NoWait:    viper.GetBool("no-wait"),
+		DryRun:    viper.GetBool("dry-run"),
+		Retry: github.RetryPolicy{
+			MaxAttempts: attempts,
+			BaseDelay:   viper.GetDuration("retry.base_delay"),
+			MaxDelay:    viper.GetDuration("retry.max_delay"),
+			Jitter:      jitter,
+			StatusCodes: cast.ToIntSlice(viper.Get("retry.status_codes")),
+			Methods:     viper.GetStringSlice("retry.methods"),
+		},
+	})
+}
+
+// bindFlags binds the keys to the flags of the running command, as commands share the names of their flags.
+func bindFlags(cmd *cobra.Command, names ...string) {
+	for _, name := range names {
+		_ = viper.BindPFlag(name, cmd.PersistentFlags().Lookup(name))
+	}
+}
+
+// releaseId parses the release id given by the --id flag.
+func releaseId() (int64, error) {
+
+	value := viper.GetString("id")
+	if value == "" {
+		return 0, usageErrorf("id is required")
+	}
+
+	id, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, usageErrorf("invalid release id %q", value)
+	}
+
+	return id, nil
+}
+
+// selectRelease returns the release given by the --tag or --id flag, or the latest release when neither is set.
+func selectRelease(client *github.Client, owner string, repo string) (*github.Release, error) {
+
+	tag := viper.GetString("tag")
+	id := viper.GetString("id")
+
+	switch {
+	case tag != "":
+		return client.GetReleaseByTag(owner, repo, tag)
+	case id == "" || id == "latest":
+		return client.GetLatestRelease(owner, repo)
+	}
+
+	releaseId, err := releaseId()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.GetRelease(owner, repo, releaseId)
 }
@@ -3,11 +3,11 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
-	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
-	"github.com/xykong/github-release/github"
+	"github.com/xykong/github-release/utils"
 )
 
 // showCmd represents the show command
@@ -26,27 +26,24 @@ drafted or published.
 Get a published release with the specified tag.
 `,
 
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
+
+		_ = viper.BindPFlag("id", cmd.PersistentFlags().Lookup("id"))
+		_ = viper.BindPFlag("tag", cmd.PersistentFlags().Lookup("tag"))
 
 		owner := viper.GetString("user")
 		repo := viper.GetString("repo")
-		releaseId := viper.GetString("id")
-		tag := viper.GetString("tag")
-
-		fmt.Printf("show called: %v, %s, %s, %s\n", args, owner, repo, releaseId)
 
-		var err error
-		if tag != "" {
-			_, err = github.GetReleaseByTag(owner, repo, tag)
-		} else {
-			_, err = github.GetRelease(owner, repo, releaseId)
-		}
+		utils.Verbose("show called: %v, %s, %s\n", args, owner, repo)
 
+		release, err := selectRelease(newClient(), owner, repo)
 		if err != nil {
-			logrus.WithFields(logrus.Fields{
-				"error": err,
-			}).Error("show called")
+			return err
 		}
+
+		result, _ := json.MarshalIndent(release, "", "\t")
+		fmt.Printf("%s\n", string(result))
+		return nil
 	},
 }
 
@@ -0,0 +1,179 @@
+// Copyright © 2019 xykong <xy.kong@gmail.com>
+
+package cmd
+
+import (
+	"fmt"
+	"github.com/mitchellh/go-homedir"
+	"github.com/spf13/viper"
+	"github.com/xykong/github-release/github"
+	"github.com/xykong/github-release/sign"
+	"github.com/xykong/github-release/utils"
+	"io"
+	"strings"
+)
+
+// newSigners loads the signing keys of the config file:
+//
+//	sign:
+//	  pgp_key: ~/.gnupg/release.asc   # armored or binary OpenPGP secret key
+//	  ssh_key: ~/.ssh/id_ed25519      # OpenSSH private key, signs like ssh-keygen -Y sign
+//	  ssh_namespace: file
+//
+// The passphrase of the keys is read from sign.passphrase or GITHUB_RELEASE_SIGN_PASSPHRASE.
+func newSigners() ([]sign.Signer, error) {
+
+	passphrase := []byte(viper.GetString("sign.passphrase"))
+
+	var signers []sign.Signer
+
+	if path := viper.GetString("sign.pgp_key"); path != "" {
+		path, err := homedir.Expand(path)
+		if err != nil {
+			return nil, err
+		}
+		signer, err := sign.NewPGPSigner(path, passphrase)
+		if err != nil {
+			return nil, err
+		}
+		signers = append(signers, signer)
+	}
+
+	if path := viper.GetString("sign.ssh_key"); path != "" {
+		path, err := homedir.Expand(path)
+		if err != nil {
+			return nil, err
+		}
+		signer, err := sign.NewSSHSigner(path, passphrase, viper.GetString("sign.ssh_namespace"))
+		if err != nil {
+			return nil, err
+		}
+		signers = append(signers, signer)
+	}
+
+	if len(signers) == 0 {
+		return nil, usageErrorf("no signing key, set sign.pgp_key or sign.ssh_key in the config file")
+	}
+
+	return signers, nil
+}
+
+// signFile writes a signature of the file per signer to dir, named after the asset name of the file,
+// and returns the upload requests of the signatures.
+func signFile(signers []sign.Signer, path string, name string, dir string, label string) ([]*github.RequestUploadAsset, error) {
+
+	var requests []*github.RequestUploadAsset
+	for _, signer := range signers {
+
+		sigPath, err := sign.File(signer, path, name, dir)
+		if err != nil {
+			return nil, err
+		}
+
+		utils.Debug("sign %s: %s", path, sigPath)
+		requests = append(requests, &github.RequestUploadAsset{Filename: sigPath, Label: label})
+	}
+
+	return requests, nil
+}
+
+// newVerifiers loads the trusted keys of the config file:
+//
+//	sign:
+//	  pgp_keyring: ~/.gnupg/release-keys.asc   # OpenPGP public keys, e.g. gpg --export --armor
+//	  allowed_signers: ~/.ssh/allowed_signers  # allowed signers file of ssh-keygen -Y verify
+//	  ssh_namespace: file
+func newVerifiers() ([]sign.Verifier, error) {
+
+	var verifiers []sign.Verifier
+
+	if path := viper.GetString("sign.pgp_keyring"); path != "" {
+		path, err := homedir.Expand(path)
+		if err != nil {
+			return nil, err
+		}
+		verifier, err := sign.NewPGPVerifier(path)
+		if err != nil {
+			return nil, err
+		}
+		verifiers = append(verifiers, verifier)
+	}
+
+	if path := viper.GetString("sign.allowed_signers"); path != "" {
+		path, err := homedir.Expand(path)
+		if err != nil {
+			return nil, err
+		}
+		verifier, err := sign.NewSSHVerifier(path, viper.GetString("sign.ssh_namespace"))
+		if err != nil {
+			return nil, err
+		}
+		verifiers = append(verifiers, verifier)
+	}
+
+	if len(verifiers) == 0 {
+		return nil, usageErrorf("no trusted keys, set sign.pgp_keyring or sign.allowed_signers in the config file")
+	}
+
+	return verifiers, nil
+}
+
+// signatureChecker verifies files against the signature assets of a release.
+type signatureChecker struct {
+	client    *github.Client
+	owner     string
+	repo      string
+	assets    github.Assets
+	verifiers []sign.Verifier
+}
+
+// verify checks every signature of the asset made with a kind of key the checker trusts,
+// and fails with sign.ErrUnsigned when there is none. open returns the content of the asset.
+func (c *signatureChecker) verify(name string, open func() (io.ReadCloser, error)) error {
+
+	var verified bool
+	for _, verifier := range c.verifiers {
+
+		asset := findAsset(c.assets, name+verifier.Extension())
+		if asset == nil {
+			continue
+		}
+
+		signature, err := c.client.FetchAsset(c.owner, c.repo, asset)
+		if err != nil {
+			return err
+		}
+
+		message, err := open()
+		if err != nil {
+			return err
+		}
+
+		signer, err := verifier.Verify(message, signature)
+		_ = message.Close()
+		if err != nil {
+			return fmt.Errorf("%s: %w", asset.Name, err)
+		}
+
+		utils.Infof(utils.Fields{
+			"name":   name,
+			"signer": signer,
+		}, "verify signature %s success", asset.Name)
+		verified = true
+	}
+
+	if !verified {
+		return fmt.Errorf("%s: %w", name, sign.ErrUnsigned)
+	}
+
+	return nil
+}
+
+// isSignature reports whether the asset is a detached signature.
+func isSignature(name string) bool {
+	return strings.HasSuffix(name, ".asc") || strings.HasSuffix(name, ".sig")
+}
+
+func init() {
+	_ = viper.BindEnv("sign.passphrase", "GITHUB_RELEASE_SIGN_PASSPHRASE")
+}
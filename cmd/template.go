@@ -0,0 +1,192 @@
+// Copyright © 2019 xykong <xy.kong@gmail.com>
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/xykong/github-release/git"
+	"github.com/xykong/github-release/github"
+	"github.com/xykong/github-release/semver"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// templateData is the data of the Go templates of release names, bodies and asset names and labels.
+type templateData struct {
+	Tag         string // The tag of the release, e.g. v1.2.3.
+	Version     string // The tag without a leading v, e.g. 1.2.3.
+	Major       uint64 // The major, minor and patch parts and the pre-release of the version,
+	Minor       uint64 // when it is a semantic version, e.g. 1, 2, 3 and rc.1 of 1.2.3-rc.1.
+	Patch       uint64
+	Prerelease  string
+	PreviousTag string            // The tag before Tag in the local git repository.
+	Commit      string            // The commit checked out in the local git repository.
+	ShortCommit string            // The commit abbreviated to 7 characters.
+	Branch      string            // The branch checked out in the local git repository.
+	Date        string            // The current date, e.g. 2019-06-30.
+	Os          string            // The operating system running the command, e.g. linux.
+	Arch        string            // The architecture running the command, e.g. amd64.
+	Env         map[string]string // The environment variables.
+	Assets      []string          // The names of the uploaded assets, not set in asset names and labels.
+	File        string            // The base name of the uploaded file, only set in asset names and labels.
+}
+
+// templateFuncs are the functions of the templates, besides the builtin ones of text/template.
+var templateFuncs = template.FuncMap{
+	"lower":      strings.ToLower,
+	"upper":      strings.ToUpper,
+	"trim":       strings.TrimSpace,
+	"trimPrefix": func(prefix string, s string) string { return strings.TrimPrefix(s, prefix) },
+	"trimSuffix": func(suffix string, s string) string { return strings.TrimSuffix(s, suffix) },
+	"replace":    func(old string, new string, s string) string { return strings.ReplaceAll(s, old, new) },
+	"join":       func(sep string, list []string) string { return strings.Join(list, sep) },
+	"env":        os.Getenv,
+	"date":       func(layout string) string { return time.Now().Format(layout) },
+	"default": func(value string, s string) string {
+		if s == "" {
+			return value
+		}
+		return s
+	},
+}
+
+// templater renders templates for the release of a tag. The data is only gathered when a text is a template.
+type templater struct {
+	tag    string
+	assets []string
+	data   *templateData
+}
+
+func newTemplater(tag string) *templater {
+	return &templater{tag: tag}
+}
+
+// render executes the text as a template, a text without actions is returned as it is.
+func (t *templater) render(name string, text string) (string, error) {
+
+	if !strings.Contains(text, "{{") {
+		return text, nil
+	}
+
+	return t.execute(name, text, t.templateData())
+}
+
+// renderAssets renders the names and labels of the upload requests, with File set to the base name of each
+// file, and then sets Assets to the names for the templates rendered later.
+func (t *templater) renderAssets(files []*github.RequestUploadAsset) error {
+
+	for _, file := range files {
+
+		var data *templateData
+		if strings.Contains(file.Name, "{{") || strings.Contains(file.Label, "{{") {
+			data = &templateData{}
+			*data = *t.templateData()
+			data.Assets = nil
+			data.File = filepath.Base(file.Filename)
+		}
+
+		var err error
+		if file.Name, err = t.execute("asset name", file.Name, data); err != nil {
+			return err
+		}
+		if file.Label, err = t.execute("asset label", file.Label, data); err != nil {
+			return err
+		}
+
+		name := assetName(file)
+		for _, other := range t.assets {
+			if other == name {
+				return usageErrorf("more than one file is uploaded as %s", name)
+			}
+		}
+		t.assets = append(t.assets, name)
+	}
+
+	if t.data != nil {
+		t.data.Assets = t.assets
+	}
+
+	return nil
+}
+
+func (t *templater) execute(name string, text string, data *templateData) (string, error) {
+
+	if !strings.Contains(text, "{{") {
+		return text, nil
+	}
+
+	tmpl, err := template.New(name).Funcs(templateFuncs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", usageErrorf("invalid template: %v", err)
+	}
+
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", usageErrorf("invalid template: %v", err)
+	}
+
+	return b.String(), nil
+}
+
+// renderRelease renders the name of the release request. The body is only rendered with --template, by
+// readBody.
+func (t *templater) renderRelease(request *github.RequestCreateRelease) error {
+
+	var err error
+	request.Name, err = t.render("name", request.Name)
+	return err
+}
+
+// addTemplateFlag adds --template, which opts in to rendering the body, as a body may mention {{ for other
+// reasons, e.g. a GitHub Actions expression.
+func addTemplateFlag(cmd *cobra.Command, usage string) {
+	cmd.PersistentFlags().BoolP("template", "", false, usage)
+	_ = viper.BindPFlag("template", cmd.PersistentFlags().Lookup("template"))
+}
+
+// templateData gathers the data once. Data missing, e.g. outside of a git repository, is left empty.
+func (t *templater) templateData() *templateData {
+
+	if t.data != nil {
+		return t.data
+	}
+
+	d := &templateData{
+		Tag:     t.tag,
+		Version: strings.TrimPrefix(t.tag, "v"),
+		Date:    time.Now().Format("2006-01-02"),
+		Os:      runtime.GOOS,
+		Arch:    runtime.GOARCH,
+		Env:     map[string]string{},
+		Assets:  t.assets,
+	}
+
+	if v, err := semver.Parse(d.Version); err == nil {
+		d.Major, d.Minor, d.Patch = v.Major, v.Minor, v.Patch
+		d.Prerelease = strings.Join(v.Prerelease, ".")
+	}
+
+	if commit, err := git.Head(); err == nil {
+		d.Commit = commit
+		d.ShortCommit = commit
+		if len(commit) > 7 {
+			d.ShortCommit = commit[:7]
+		}
+		d.Branch, _ = git.Branch()
+		d.PreviousTag, _ = git.PreviousTag(git.TagOrHead(t.tag))
+	}
+
+	for _, kv := range os.Environ() {
+		if i := strings.IndexByte(kv, '='); i > 0 {
+			d.Env[kv[:i]] = kv[i+1:]
+		}
+	}
+
+	t.data = d
+	return d
+}
@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"errors"
+	"github.com/xykong/github-release/github"
+	"os"
+	"runtime"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestTemplaterRender(t *testing.T) {
+
+	if err := os.Setenv("GITHUB_RELEASE_TEST", "from the environment"); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.Unsetenv("GITHUB_RELEASE_TEST") }()
+	_ = os.Unsetenv("GITHUB_RELEASE_UNSET")
+
+	tests := []struct {
+		text    string
+		want    string
+		wantErr bool
+	}{
+		{text: "{{.Tag}} {{.Version}}", want: "v1.2.3-rc.1 1.2.3-rc.1"},
+		{text: "{{.Major}}.{{.Minor}}.{{.Patch}} {{.Prerelease}}", want: "1.2.3 rc.1"},
+		{text: "{{.Os}}/{{.Arch}}", want: runtime.GOOS + "/" + runtime.GOARCH},
+
+		{text: `{{upper .Tag}} {{lower "V1"}} [{{trim "  x  "}}]`, want: "V1.2.3-RC.1 v1 [x]"},
+		{text: `{{.Tag | trimPrefix "v"}} {{.Tag | trimSuffix "-rc.1"}}`, want: "1.2.3-rc.1 v1.2.3"},
+		{text: `{{replace "." "_" .Version}}`, want: "1_2_3-rc_1"},
+		{text: `{{env "GITHUB_RELEASE_TEST"}} {{.Env.GITHUB_RELEASE_TEST}}`, want: "from the environment from the environment"},
+		{text: `[{{env "GITHUB_RELEASE_UNSET"}}] {{env "GITHUB_RELEASE_UNSET" | default "none"}}`, want: "[] none"},
+		{text: `{{.Tag | default "none"}}`, want: "v1.2.3-rc.1"},
+		{text: `{{date "2006"}}`, want: strconv.Itoa(time.Now().Year())},
+
+		// Texts without actions are not templates.
+		{text: "Release notes", want: "Release notes"},
+		{text: "${ secrets.TOKEN } {.Tag}", want: "${ secrets.TOKEN } {.Tag}"},
+		{text: "", want: ""},
+
+		// missingkey=error rejects unknown environment variables as well as unknown fields.
+		{text: "{{.Env.GITHUB_RELEASE_UNSET}}", wantErr: true},
+		{text: "{{.Tags}}", wantErr: true},
+		{text: "{{.Tag", wantErr: true},
+		{text: "{{nosuchfunc .Tag}}", wantErr: true},
+	}
+
+	for _, test := range tests {
+
+		got, err := newTemplater("v1.2.3-rc.1").render("name", test.text)
+		if test.wantErr {
+			var usage *usageError
+			if !errors.As(err, &usage) {
+				t.Errorf("render(%q) = %q, %v, want a usage error", test.text, got, err)
+			}
+			continue
+		}
+		if err != nil || got != test.want {
+			t.Errorf("render(%q) = %q, %v, want %q", test.text, got, err, test.want)
+		}
+	}
+}
+
+func TestTemplaterGathersDataOfTemplatesOnly(t *testing.T) {
+
+	tmpl := newTemplater("v1.0.0")
+	if _, err := tmpl.render("body", "no template"); err != nil {
+		t.Fatal(err)
+	}
+	if tmpl.data != nil {
+		t.Error("data gathered for a text without actions")
+	}
+}
+
+func TestTemplaterRenderAssets(t *testing.T) {
+
+	files := []*github.RequestUploadAsset{
+		{Filename: "dist/tool.tar.gz", Name: `{{.File | trimSuffix ".tar.gz"}}_{{.Tag}}.tar.gz`, Label: "{{.File}}"},
+		{Filename: "dist/tool.zip", Label: "{{len .Assets}} assets so far"},
+		{Filename: "build/README", Name: "readme.txt"},
+	}
+
+	tmpl := newTemplater("v1.0.0")
+	if err := tmpl.renderAssets(files); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []github.RequestUploadAsset{
+		{Filename: "dist/tool.tar.gz", Name: "tool_v1.0.0.tar.gz", Label: "tool.tar.gz"},
+		{Filename: "dist/tool.zip", Label: "0 assets so far"},
+		{Filename: "build/README", Name: "readme.txt"},
+	}
+	for i, file := range files {
+		if file.Name != want[i].Name || file.Label != want[i].Label {
+			t.Errorf("file %s: name %q, label %q, want %q, %q", file.Filename, file.Name, file.Label, want[i].Name, want[i].Label)
+		}
+	}
+
+	// Templates rendered later list the names of the assets, and have no File.
+	body, err := tmpl.render("body", `{{join ", " .Assets}}{{.File}}`)
+	if err != nil || body != "tool_v1.0.0.tar.gz, tool.zip, readme.txt" {
+		t.Errorf("body %q, %v, want the names of the assets", body, err)
+	}
+}
+
+func TestTemplaterRenderAssetsDuplicate(t *testing.T) {
+
+	tests := []struct {
+		name  string
+		files []*github.RequestUploadAsset
+	}{
+		{"same base name", []*github.RequestUploadAsset{
+			{Filename: "linux/tool"},
+			{Filename: "darwin/tool"},
+		}},
+		{"same rendered name", []*github.RequestUploadAsset{
+			{Filename: "dist/tool-linux", Name: "tool_{{.Tag}}"},
+			{Filename: "dist/tool-darwin", Name: "tool_{{.Tag}}"},
+		}},
+		{"name of another file", []*github.RequestUploadAsset{
+			{Filename: "dist/tool-linux", Name: "tool"},
+			{Filename: "build/tool"},
+		}},
+	}
+
+	for _, test := range tests {
+
+		err := newTemplater("v1.0.0").renderAssets(test.files)
+
+		var usage *usageError
+		if !errors.As(err, &usage) {
+			t.Errorf("%s: renderAssets = %v, want a usage error", test.name, err)
+		}
+	}
+}
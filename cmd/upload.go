@@ -15,11 +15,19 @@
 package cmd
 
 import (
+	"fmt"
+	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"github.com/xykong/github-release/checksum"
 	"github.com/xykong/github-release/github"
+	"github.com/xykong/github-release/sign"
 	"github.com/xykong/github-release/utils"
+	"io/ioutil"
 	"os"
+	"path/filepath"
+	"strings"
+	"time"
 )
 
 // uploadCmd represents the upload command
@@ -32,25 +40,300 @@ and usage of using your command. For example:
 Cobra is a CLI library for Go that empowers applications.
 This application is a tool to generate the needed files
 to quickly create a Cobra application.`,
-	Args: cobra.MinimumNArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
+	Args: usageArgs(cobra.MinimumNArgs(1)),
+	RunE: func(cmd *cobra.Command, args []string) error {
 
-		_ = viper.BindPFlag("id", cmd.PersistentFlags().Lookup("id"))
+		bindFlags(cmd, "id", "asset-name", "label", "parallel", "keep-going", "checksum", "sign", "plan")
 
 		owner := viper.GetString("user")
 		repo := viper.GetString("repo")
-		label := viper.GetString("label")
 
 		utils.Verbose("upload called: %v, %s, %s\n", args, owner, repo)
 
-		for _, name := range args {
-			err := github.UploadAsset(owner, repo, name, label)
+		id, err := releaseId()
+		if err != nil {
+			return err
+		}
+
+		client := newClient()
+
+		release, err := client.GetRelease(owner, repo, id)
+		if err != nil {
+			return err
+		}
+
+		files := fileRequests(args, viper.GetString("asset-name"), viper.GetString("label"))
+		if err := newTemplater(release.TagName).renderAssets(files); err != nil {
+			return err
+		}
+
+		options, err := newUploadOptions()
+		if err != nil {
+			return err
+		}
+
+		if viper.GetBool("plan") {
+			return planUpload(client, owner, repo, release, files, options)
+		}
+
+		return options.upload(client, owner, repo, release, files, false)
+	},
+}
+
+// uploadOptions are the flags shared by the commands uploading files.
+type uploadOptions struct {
+	parallel  int
+	keepGoing bool
+	algorithm checksum.Algorithm
+	signers   []sign.Signer
+}
+
+// newUploadOptions reads the upload flags, and loads the signing keys for --sign.
+func newUploadOptions() (*uploadOptions, error) {
+
+	options := &uploadOptions{
+		parallel:  viper.GetInt("parallel"),
+		keepGoing: viper.GetBool("keep-going"),
+	}
+
+	if name := viper.GetString("checksum"); name != "" {
+		algorithm, err := checksum.ParseAlgorithm(name)
+		if err != nil {
+			return nil, &usageError{err: err}
+		}
+		options.algorithm = algorithm
+	}
+
+	if viper.GetBool("sign") {
+		signers, err := newSigners()
+		if err != nil {
+			return nil, err
+		}
+		options.signers = signers
+	}
+
+	return options, nil
+}
+
+// fileRequests returns the upload requests of files, named after their base name when name is empty.
+func fileRequests(files []string, name string, label string) []*github.RequestUploadAsset {
+
+	var requests []*github.RequestUploadAsset
+	for _, file := range files {
+		requests = append(requests, &github.RequestUploadAsset{Filename: file, Name: name, Label: label})
+	}
+
+	return requests
+}
+
+// upload uploads the files with their signatures to the release and publishes the checksum manifest.
+// With replace, assets of the release with the names of the files are replaced once their files are uploaded.
+func (o *uploadOptions) upload(client *github.Client, owner string, repo string, release *github.Release,
+	files []*github.RequestUploadAsset, replace bool) error {
+
+	var sigDir string
+	if o.signers != nil {
+		var err error
+		if sigDir, err = ioutil.TempDir("", "github-release"); err != nil {
+			return err
+		}
+
+		//noinspection GoUnhandledErrorResult
+		defer os.RemoveAll(sigDir)
+	}
+
+	var requests []*github.RequestUploadAsset
+	var progresses []*utils.Progress
+	signatures := map[*github.RequestUploadAsset]bool{}
+	for _, request := range files {
+		name := assetName(request)
+
+		progress := utils.NewProgress(name, o.parallel <= 1)
+		progresses = append(progresses, progress)
+		request.Progress = progress.Update
+		requests = append(requests, request)
+
+		if o.signers != nil {
+			sigRequests, err := signFile(o.signers, request.Filename, name, sigDir, request.Label)
 			if err != nil {
-				utils.Error("upload called: %v", err)
-				os.Exit(1)
+				return err
 			}
+			for _, request := range sigRequests {
+				signatures[request] = true
+			}
+			requests = append(requests, sigRequests...)
 		}
-	},
+	}
+
+	var replacements map[*github.RequestUploadAsset]*replacement
+	if replace {
+		assets, err := client.ListAssets(owner, repo, release.Id, nil)
+		if err != nil {
+			return err
+		}
+		replacements = stageReplacements(assets, requests)
+	}
+
+	results := client.UploadReleaseAssets(owner, repo, release, requests, o.parallel, o.keepGoing)
+	for _, progress := range progresses {
+		progress.Done()
+	}
+
+	for i := range results {
+		r := &results[i]
+		if replaced := replacements[r.Request]; replaced != nil && r.Err == nil {
+			r.Asset, r.Err = replaced.swap(client, owner, repo, r.Asset)
+		}
+	}
+
+	err := uploadSummary(results)
+
+	if o.algorithm != "" {
+		// The signatures are not recorded in the manifest, which is signed itself.
+		var uploaded []github.UploadResult
+		for _, r := range results {
+			if !signatures[r.Request] {
+				uploaded = append(uploaded, r)
+			}
+		}
+
+		if manifestErr := publishManifest(client, owner, repo, release, o.algorithm, o.signers, uploaded); err == nil {
+			err = manifestErr
+		}
+	}
+
+	return err
+}
+
+// assetName returns the name the file of the request is uploaded as.
+func assetName(request *github.RequestUploadAsset) string {
+
+	if request.Name != "" {
+		return request.Name
+	}
+
+	return filepath.Base(request.Filename)
+}
+
+// replacement is an asset of the release that an upload replaces.
+type replacement struct {
+	name string
+	old  *github.Asset
+}
+
+// stageReplacements uploads the requests whose names are among the assets under a temporary name, so the
+// old assets stay until their replacements are complete.
+func stageReplacements(assets github.Assets, requests []*github.RequestUploadAsset) map[*github.RequestUploadAsset]*replacement {
+
+	replacements := map[*github.RequestUploadAsset]*replacement{}
+	for _, request := range requests {
+
+		name := assetName(request)
+		existing := findAsset(assets, name)
+		if existing == nil {
+			continue
+		}
+
+		replacements[request] = &replacement{name: name, old: existing}
+		request.Name = temporaryName(name)
+	}
+
+	return replacements
+}
+
+// temporaryName returns a name to upload the replacement of the asset with the name as, unique to the run so
+// an upload left behind by an earlier one is not taken for it.
+func temporaryName(name string) string {
+	return fmt.Sprintf("%s.%d.tmp", name, time.Now().UnixNano())
+}
+
+// swap deletes the replaced asset and gives the uploaded replacement its name.
+func (r *replacement) swap(client *github.Client, owner string, repo string, uploaded *github.Asset) (*github.Asset, error) {
+
+	if err := client.DeleteAsset(owner, repo, r.old.Id); err != nil {
+		return nil, err
+	}
+
+	utils.Infof(utils.Fields{
+		"id":   r.old.Id,
+		"name": r.old.Name,
+	}, "delete the replaced asset")
+
+	return client.EditAsset(owner, repo, uploaded.Id, &github.RequestEditAsset{Name: r.name})
+}
+
+// planUpload prints the changes the upload of the files is about to make to the release.
+func planUpload(client *github.Client, owner string, repo string, release *github.Release,
+	files []*github.RequestUploadAsset, options *uploadOptions) error {
+
+	p, err := newPlan(client, owner, repo, release)
+	if err != nil {
+		return err
+	}
+
+	planned, replaced, err := p.planned(nil, files, options)
+	if err != nil {
+		return err
+	}
+
+	p.print(planned)
+
+	// Only the checksum manifest and its signatures are replaced, the other assets are not overwritten.
+	for _, name := range replaced {
+		if isManifest(strings.TrimSuffix(strings.TrimSuffix(name, ".asc"), ".sig")) {
+			fmt.Printf("# the asset %s is uploaded again and replaces the existing one\n", name)
+		} else {
+			fmt.Printf("# an asset %s exists, its upload fails\n", name)
+		}
+	}
+
+	return nil
+}
+
+// uploadSummary prints a line per uploaded file and returns an error when any of them failed.
+func uploadSummary(results []github.UploadResult) error {
+
+	var failed int
+	var first error
+
+	quiet := viper.GetBool("essential")
+	if !quiet {
+		color.Green("%-6s    %10s    %8s    %-32s    %s\n", "status", "size", "time", "name", "error")
+	}
+
+	for _, r := range results {
+
+		status, size, message := "ok", "", ""
+		if r.Asset != nil {
+			size = utils.FormatBytes(r.Asset.Size)
+		}
+
+		switch {
+		case r.Err == github.ErrSkipped:
+			status, message = "skip", r.Err.Error()
+		case r.Err != nil:
+			status, message = "failed", r.Err.Error()
+			failed++
+			if first == nil {
+				first = fmt.Errorf("upload %s: %w", r.Request.Filename, r.Err)
+			}
+		}
+
+		if !quiet {
+			fmt.Printf("%-6s    %10s    %8s    %-32s    %s\n",
+				status, size, r.Duration.Round(time.Millisecond), r.Request.Filename, message)
+		}
+	}
+
+	if failed == 0 {
+		return nil
+	}
+
+	if failed == 1 {
+		return first
+	}
+
+	return fmt.Errorf("%d of %d uploads failed, first: %w", failed, len(results), first)
 }
 
 func init() {
@@ -62,12 +345,30 @@ func init() {
 	// and all subcommands, e.g.:
 	// uploadCmd.PersistentFlags().String("foo", "", "A help for foo")
 
+	uploadCmd.PersistentFlags().StringP("asset-name", "", "", "The name of the assets, a template, default: the base name of the file")
+	_ = viper.BindPFlag("asset-name", uploadCmd.PersistentFlags().Lookup("asset-name"))
+
 	uploadCmd.PersistentFlags().StringP("label", "l", "", "The id of the release")
 	_ = viper.BindPFlag("label", uploadCmd.PersistentFlags().Lookup("label"))
 
 	uploadCmd.PersistentFlags().StringP("id", "i", "", "The id of the release")
 	_ = viper.BindPFlag("id", uploadCmd.PersistentFlags().Lookup("id"))
 
+	uploadCmd.PersistentFlags().IntP("parallel", "p", 1, "Number of files uploaded at the same time")
+	_ = viper.BindPFlag("parallel", uploadCmd.PersistentFlags().Lookup("parallel"))
+
+	uploadCmd.PersistentFlags().BoolP("keep-going", "k", false, "Continue uploading the remaining files after a failure")
+	_ = viper.BindPFlag("keep-going", uploadCmd.PersistentFlags().Lookup("keep-going"))
+
+	uploadCmd.PersistentFlags().StringP("checksum", "", "", "Publish a SHA256SUMS or SHA512SUMS manifest of the files: sha256 or sha512")
+	_ = viper.BindPFlag("checksum", uploadCmd.PersistentFlags().Lookup("checksum"))
+
+	uploadCmd.PersistentFlags().BoolP("plan", "", false, "Print the changes to the release instead of making them")
+	_ = viper.BindPFlag("plan", uploadCmd.PersistentFlags().Lookup("plan"))
+
+	uploadCmd.PersistentFlags().BoolP("sign", "", false, "Upload detached signatures (.asc, .sig) of the files and the checksum manifest, with the keys of the config file")
+	_ = viper.BindPFlag("sign", uploadCmd.PersistentFlags().Lookup("sign"))
+
 	// Cobra supports local flags which will only run when this command
 	// is called directly, e.g.:
 	// uploadCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
@@ -0,0 +1,145 @@
+package git
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Commit is a commit message parsed as a Conventional Commit, e.g. "feat(upload)!: add --parallel".
+type Commit struct {
+	Hash     string
+	Type     string
+	Scope    string
+	Subject  string
+	Breaking bool
+}
+
+// conventional matches the header of a Conventional Commit: type, optional scope, optional "!" and subject.
+var conventional = regexp.MustCompile(`^(\w+)(?:\(([^)]*)\))?(!)?: +(.+)$`)
+
+// breakingFooter matches the footer of a commit body announcing a breaking change.
+var breakingFooter = regexp.MustCompile(`(?m)^BREAKING[ -]CHANGE: `)
+
+// ParseCommit parses the subject and body of a commit message, and reports whether it is a Conventional Commit.
+func ParseCommit(hash string, subject string, body string) (Commit, bool) {
+
+	m := conventional.FindStringSubmatch(strings.TrimSpace(subject))
+	if m == nil {
+		return Commit{}, false
+	}
+
+	return Commit{
+		Hash:     hash,
+		Type:     strings.ToLower(m[1]),
+		Scope:    m[2],
+		Subject:  m[4],
+		Breaking: m[3] != "" || breakingFooter.MatchString(body),
+	}, true
+}
+
+// Log returns the Conventional Commits reachable from to but not from from, newest first, and the number
+// of other commits skipped. An empty from includes the whole history of to.
+func Log(from string, to string) ([]Commit, int, error) {
+
+	rng := to
+	if from != "" {
+		rng = from + ".." + to
+	}
+
+	// Fields are separated by the unit separator and commits by the record separator.
+	out, err := Run("log", "--no-merges", "--format=%H%x1f%s%x1f%b%x1e", rng)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var commits []Commit
+	skipped := 0
+	for _, record := range strings.Split(out, "\x1e") {
+
+		fields := strings.SplitN(strings.TrimLeft(record, "\n"), "\x1f", 3)
+		if len(fields) < 3 {
+			continue
+		}
+
+		if commit, ok := ParseCommit(fields[0], fields[1], fields[2]); ok {
+			commits = append(commits, commit)
+		} else {
+			skipped++
+		}
+	}
+
+	return commits, skipped, nil
+}
+
+// sections are the sections of the changelog in order, with the commit types they list.
+var sections = []struct {
+	title string
+	match func(c Commit) bool
+}{
+	{"Breaking changes", func(c Commit) bool { return c.Breaking }},
+	{"Features", func(c Commit) bool { return c.Type == "feat" && !c.Breaking }},
+	{"Bug fixes", func(c Commit) bool { return c.Type == "fix" && !c.Breaking }},
+	{"Performance improvements", func(c Commit) bool { return c.Type == "perf" && !c.Breaking }},
+}
+
+// Markdown renders the commits as a markdown changelog, one section per kind of change:
+//
+//	## Features
+//
+//	- **upload:** add --parallel (1a2b3c4)
+//
+// Commits of other types, e.g. chore or docs, are left out.
+func Markdown(commits []Commit) string {
+
+	var b strings.Builder
+	for _, section := range sections {
+
+		var items []string
+		for _, c := range commits {
+			if !section.match(c) {
+				continue
+			}
+
+			item := "- "
+			if c.Scope != "" {
+				item += "**" + c.Scope + ":** "
+			}
+			item += c.Subject
+			if len(c.Hash) >= 7 {
+				item += " (" + c.Hash[:7] + ")"
+			}
+			items = append(items, item)
+		}
+
+		if len(items) == 0 {
+			continue
+		}
+
+		if b.Len() > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "## %s\n\n%s\n", section.title, strings.Join(items, "\n"))
+	}
+
+	return b.String()
+}
+
+// Changelog returns the markdown changelog of the commits since the tag before the given one, and that
+// previous tag, which is empty when there is none. When the tag does not exist yet the commits up to HEAD
+// are listed.
+func Changelog(tag string) (string, string, error) {
+
+	to := TagOrHead(tag)
+	previous, err := PreviousTag(to)
+	if err != nil && err != ErrNoTag {
+		return "", "", err
+	}
+
+	commits, _, err := Log(previous, to)
+	if err != nil {
+		return "", "", err
+	}
+
+	return Markdown(commits), previous, nil
+}
@@ -0,0 +1,158 @@
+package git
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func TestParseCommit(t *testing.T) {
+
+	tests := []struct {
+		subject string
+		body    string
+		want    Commit
+		ok      bool
+	}{
+		{subject: "feat: add --parallel", want: Commit{Type: "feat", Subject: "add --parallel"}, ok: true},
+		{subject: "fix(upload): retry on 502", want: Commit{Type: "fix", Scope: "upload", Subject: "retry on 502"}, ok: true},
+		{subject: "Feat(Upload): keep the case of the scope", want: Commit{Type: "feat", Scope: "Upload",
+			Subject: "keep the case of the scope"}, ok: true},
+		{subject: "  perf:   trim spaces  ", want: Commit{Type: "perf", Subject: "trim spaces"}, ok: true},
+		{subject: "chore(deps)!: drop Go 1.12", want: Commit{Type: "chore", Scope: "deps", Subject: "drop Go 1.12",
+			Breaking: true}, ok: true},
+		{subject: "feat!: rename --uploads", want: Commit{Type: "feat", Subject: "rename --uploads", Breaking: true}, ok: true},
+
+		// Footers announcing a breaking change.
+		{subject: "feat: rename --uploads", body: "The flag is renamed.\n\nBREAKING CHANGE: use --upload-url",
+			want: Commit{Type: "feat", Subject: "rename --uploads", Breaking: true}, ok: true},
+		{subject: "fix: exit codes", body: "BREAKING-CHANGE: 4 is not found",
+			want: Commit{Type: "fix", Subject: "exit codes", Breaking: true}, ok: true},
+		{subject: "fix: exit codes", body: "This is no BREAKING CHANGE: it is mentioned in the text.",
+			want: Commit{Type: "fix", Subject: "exit codes"}, ok: true},
+		{subject: "fix: exit codes", body: "breaking change: lower case",
+			want: Commit{Type: "fix", Subject: "exit codes"}, ok: true},
+
+		// Not Conventional Commits.
+		{subject: "Add --parallel"},
+		{subject: "feat add --parallel"},
+		{subject: "feat:add --parallel"},
+		{subject: "feat(upload: add --parallel"},
+		{subject: "feat: "},
+		{subject: "Merge branch 'master'"},
+		{subject: ""},
+	}
+
+	for _, test := range tests {
+
+		got, ok := ParseCommit("1a2b3c4d", test.subject, test.body)
+		if ok != test.ok {
+			t.Errorf("ParseCommit(%q, %q) ok = %v, want %v", test.subject, test.body, ok, test.ok)
+			continue
+		}
+		if !ok {
+			continue
+		}
+
+		test.want.Hash = "1a2b3c4d"
+		if got != test.want {
+			t.Errorf("ParseCommit(%q, %q) = %+v, want %+v", test.subject, test.body, got, test.want)
+		}
+	}
+}
+
+func TestMarkdown(t *testing.T) {
+
+	commits := []Commit{
+		{Hash: "aaaaaaaaaa", Type: "feat", Scope: "upload", Subject: "add --parallel"},
+		{Hash: "bbbbbbbbbb", Type: "fix", Subject: "retry on 502"},
+		{Hash: "cccccccccc", Type: "chore", Subject: "update dependencies"},
+		{Hash: "dddddddddd", Type: "fix", Scope: "cli", Subject: "rename --uploads", Breaking: true},
+		{Hash: "eeeeeeeeee", Type: "perf", Subject: "stream uploads"},
+		{Hash: "ffffffffff", Type: "docs", Subject: "document exit codes", Breaking: true},
+		{Hash: "short", Type: "feat", Subject: "without a hash"},
+	}
+
+	want := `## Breaking changes
+
+- **cli:** rename --uploads (ddddddd)
+- document exit codes (fffffff)
+
+## Features
+
+- **upload:** add --parallel (aaaaaaa)
+- without a hash
+
+## Bug fixes
+
+- retry on 502 (bbbbbbb)
+
+## Performance improvements
+
+- stream uploads (eeeeeee)
+`
+
+	if got := Markdown(commits); got != want {
+		t.Errorf("Markdown =\n%s\nwant\n%s", got, want)
+	}
+
+	if got := Markdown([]Commit{{Type: "chore", Subject: "update dependencies"}}); got != "" {
+		t.Errorf("Markdown of a chore = %q, want none", got)
+	}
+}
+
+func TestLog(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "changelog")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.Chdir(wd) }()
+
+	commit := func(message string) {
+		if _, err := Run("-c", "user.name=Release", "-c", "user.email=release@example.com",
+			"commit", "--quiet", "--allow-empty", "-m", message); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err = Run("init", "--quiet"); err != nil {
+		t.Fatal(err)
+	}
+	commit("feat: first feature")
+	if _, err = Run("tag", "v1.0.0"); err != nil {
+		t.Fatal(err)
+	}
+	commit("Update the README")
+	commit("fix(upload): retry on 502")
+	commit("feat: rename --uploads\n\nBREAKING CHANGE: use --upload-url")
+
+	commits, skipped, err := Log("v1.0.0", "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if skipped != 1 || len(commits) != 2 {
+		t.Fatalf("Log = %+v, %d skipped, want 2 commits and 1 skipped", commits, skipped)
+	}
+	if c := commits[0]; c.Type != "feat" || !c.Breaking || c.Subject != "rename --uploads" || len(c.Hash) != 40 {
+		t.Errorf("newest commit %+v, want the breaking feat", c)
+	}
+	if c := commits[1]; c.Type != "fix" || c.Scope != "upload" || c.Breaking {
+		t.Errorf("oldest commit %+v, want the fix of upload", c)
+	}
+
+	all, skipped, err := Log("", "HEAD")
+	if err != nil || len(all) != 3 || skipped != 1 {
+		t.Errorf("Log of the whole history = %d commits, %d skipped, %v, want 3 and 1", len(all), skipped, err)
+	}
+}
@@ -0,0 +1,107 @@
+// Package git reads the local git repository of the working directory with the git command.
+package git
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// ErrNoTag is returned when no tag precedes the revision.
+var ErrNoTag = errors.New("no tag")
+
+// Run runs git with the arguments and returns its output without the trailing newline.
+func Run(args ...string) (string, error) {
+
+	var stdout, stderr bytes.Buffer
+	command := exec.Command("git", args...)
+	command.Stdout = &stdout
+	command.Stderr = &stderr
+
+	if err := command.Run(); err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return "", fmt.Errorf("git %s: %s", args[0], message)
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+
+	return strings.TrimRight(stdout.String(), "\n"), nil
+}
+
+// HasRevision reports whether the revision, e.g. a tag, exists in the repository.
+func HasRevision(rev string) bool {
+	_, err := Run("rev-parse", "--verify", "--quiet", rev+"^{commit}")
+	return err == nil
+}
+
+// PreviousTag returns the latest tag reachable from the parents of the revision, so a tag of the revision
+// itself is skipped. It returns ErrNoTag when there is none.
+func PreviousTag(rev string) (string, error) {
+
+	if _, err := Run("rev-parse", "--verify", "--quiet", rev+"^"); err != nil {
+		return "", ErrNoTag // The revision is a root commit.
+	}
+
+	tag, err := Run("describe", "--tags", "--abbrev=0", rev+"^")
+	if err != nil {
+		return "", ErrNoTag
+	}
+
+	return tag, nil
+}
+
+// CommitOf returns the full hash of the commit of the revision, e.g. of an annotated tag.
+func CommitOf(rev string) (string, error) {
+	return Run("rev-parse", "--verify", rev+"^{commit}")
+}
+
+// Dirty reports whether tracked files of the working tree or the index differ from the commit checked out.
+func Dirty() (bool, error) {
+
+	out, err := Run("status", "--porcelain", "--untracked-files=no")
+	if err != nil {
+		return false, err
+	}
+
+	return out != "", nil
+}
+
+// Describe returns the tag of the commit checked out with exact, and otherwise the name git describe gives
+// it, e.g. v1.2.3-4-g1a2b3c4 for the fourth commit after v1.2.3. It returns ErrNoTag when no tag precedes
+// it.
+func Describe(exact bool) (string, error) {
+
+	args := []string{"describe", "--tags"}
+	if exact {
+		args = append(args, "--exact-match")
+	}
+
+	name, err := Run(append(args, "HEAD")...)
+	if err != nil {
+		return "", ErrNoTag
+	}
+
+	return name, nil
+}
+
+// Head returns the full hash of the commit checked out.
+func Head() (string, error) {
+	return Run("rev-parse", "HEAD")
+}
+
+// Branch returns the name of the branch checked out, HEAD when it is detached.
+func Branch() (string, error) {
+	return Run("rev-parse", "--abbrev-ref", "HEAD")
+}
+
+// TagOrHead returns the ref of the tag when it exists, and HEAD otherwise, e.g. for a tag yet to be created.
+func TagOrHead(tag string) string {
+
+	if tag != "" && HasRevision("refs/tags/"+tag) {
+		return "refs/tags/" + tag
+	}
+
+	return "HEAD"
+}
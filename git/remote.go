@@ -0,0 +1,70 @@
+package git
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Remote is a repository hosted on GitHub or GitHub Enterprise, parsed from the URL of a git remote.
+type Remote struct {
+	Name  string
+	URL   string
+	Host  string
+	Owner string
+	Repo  string
+}
+
+// FindRemote returns the URL of the remote as git uses it, with its url.<base>.insteadOf rewrites, and
+// parses it.
+func FindRemote(name string) (*Remote, error) {
+
+	raw, err := Run("remote", "get-url", name)
+	if err != nil {
+		return nil, err
+	}
+
+	remote, err := ParseRemoteURL(raw)
+	if err != nil {
+		return nil, err
+	}
+	remote.Name = name
+
+	return remote, nil
+}
+
+// ParseRemoteURL parses the owner and name of the repository from its URL, e.g. git@github.com:o/r.git,
+// ssh://git@github.example.com:2222/o/r or https://github.com/o/r.git. The owner and name are the last
+// two parts of the path.
+func ParseRemoteURL(raw string) (*Remote, error) {
+
+	remote := &Remote{URL: raw}
+
+	var path string
+	if strings.Contains(raw, "://") {
+		u, err := url.Parse(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid remote URL %q: %w", raw, err)
+		}
+		remote.URL = u.Redacted() // A token in the URL is not printed.
+		remote.Host = u.Hostname()
+		path = u.Path
+	} else if i := strings.Index(raw, ":"); i > 0 && !strings.Contains(raw[:i], "/") {
+		// The scp-like syntax of ssh: [user@]host:path.
+		remote.Host = raw[strings.LastIndex(raw[:i], "@")+1 : i]
+		path = raw[i+1:]
+	}
+
+	if remote.Host == "" {
+		return nil, fmt.Errorf("remote URL %q is not a hosted repository", remote.URL)
+	}
+
+	parts := strings.Split(strings.TrimSuffix(strings.Trim(path, "/"), ".git"), "/")
+	if len(parts) < 2 || parts[len(parts)-2] == "" || parts[len(parts)-1] == "" {
+		return nil, fmt.Errorf("remote URL %q has no owner and repository", remote.URL)
+	}
+	remote.Owner = parts[len(parts)-2]
+	remote.Repo = parts[len(parts)-1]
+
+	return remote, nil
+}
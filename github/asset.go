@@ -0,0 +1,341 @@
+package github
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/gabriel-vasile/mimetype"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type Asset struct {
+	Url                string      `json:"url"`
+	Id                 int64       `json:"id"`
+	NodeId             string      `json:"node_id"`
+	Name               string      `json:"name"`
+	Label              interface{} `json:"label"`
+	Uploader           User        `json:"uploader"`
+	ContentType        string      `json:"content_type"`
+	State              string      `json:"state"`
+	Size               int64       `json:"size"`
+	DownloadCount      int64       `json:"download_count"`
+	CreatedAt          time.Time   `json:"created_at"`
+	UpdatedAt          time.Time   `json:"updated_at"`
+	BrowserDownloadUrl string      `json:"browser_download_url"`
+}
+
+type Assets []Asset
+
+// Assets returns an iterator over the assets of a release.
+func (c *Client) Assets(owner string, repo string, releaseId int64, opts *ListOptions) *AssetIterator {
+
+	desc := "list assets for a release"
+	url := fmt.Sprintf("%s/repos/%s/%s/releases/%d/assets", c.baseURL, owner, repo, releaseId)
+
+	it := &AssetIterator{pager: c.newPager(desc, url, opts)}
+
+	err := validate(map[string]string{
+		"user": owner,
+		"repo": repo,
+	})
+	if err != nil {
+		it.pager.err = fmt.Errorf("%s: %w", desc, err)
+	}
+
+	return it
+}
+
+// ListAssets follows the pagination and returns all assets of a release, up to opts.Limit.
+func (c *Client) ListAssets(owner string, repo string, releaseId int64, opts *ListOptions) (Assets, error) {
+
+	var assets = Assets{}
+
+	it := c.Assets(owner, repo, releaseId, opts)
+	for it.Next() {
+		assets = append(assets, *it.Asset())
+	}
+
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+
+	return assets, nil
+}
+
+// ProgressFunc is called while a file is transferred with the bytes sent so far and the file size.
+type ProgressFunc func(transferred int64, total int64)
+
+type RequestUploadAsset struct {
+	Filename    string       // Required. Path of the local file to upload.
+	Name        string       // The file name of the asset. Default: the base name of Filename.
+	Label       string       // An alternate short description of the asset. Used in place of the filename.
+	ContentType string       // The media type of the asset. Default: detected from the content of the file.
+	Progress    ProgressFunc // Optional. Reports the upload progress, restarting from 0 on retries.
+}
+
+// progressReader reports the bytes read through it to a ProgressFunc.
+type progressReader struct {
+	io.ReadCloser
+	transferred int64
+	total       int64
+	progress    ProgressFunc
+}
+
+func (r *progressReader) Read(p []byte) (int, error) {
+
+	n, err := r.ReadCloser.Read(p)
+	r.transferred += int64(n)
+	r.progress(r.transferred, r.total)
+
+	return n, err
+}
+
+// openFile opens the file for streaming and returns it along with its size.
+func openFile(filename string, progress ProgressFunc) (io.ReadCloser, int64, error) {
+
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return nil, 0, err
+	}
+
+	if progress == nil {
+		return file, info.Size(), nil
+	}
+
+	progress(0, info.Size())
+	return &progressReader{ReadCloser: file, total: info.Size(), progress: progress}, info.Size(), nil
+}
+
+// UploadAsset fetches the release to learn its upload_url and uploads the file to it.
+// Use UploadReleaseAsset to upload several files without fetching the release each time.
+func (c *Client) UploadAsset(owner string, repo string, releaseId int64, request *RequestUploadAsset) (*Asset, error) {
+
+	release, err := c.GetRelease(owner, repo, releaseId)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.UploadReleaseAsset(owner, repo, release, request)
+}
+
+// UploadReleaseAsset uploads the file to the upload_url hypermedia link of the release,
+// or to the explicitly configured upload endpoint when Options.UploadURL is set.
+func (c *Client) UploadReleaseAsset(owner string, repo string, release *Release, request *RequestUploadAsset) (*Asset, error) {
+
+	desc := "upload a release asset"
+
+	name := request.Name
+	if name == "" {
+		name = filepath.Base(request.Filename)
+	}
+
+	err := validate(map[string]string{
+		"user":     owner,
+		"repo":     repo,
+		"token":    c.token,
+		"filename": request.Filename,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", desc, err)
+	}
+
+	releaseId := release.Id
+	template := release.UploadUrl
+	if c.uploadSet || template == "" {
+		template = fmt.Sprintf("%s/repos/%s/%s/releases/%d/assets{?name,label}", c.uploadURL, owner, repo, releaseId)
+	}
+
+	uri := expandURITemplate(template, map[string]string{
+		"name":  name,
+		"label": request.Label,
+	})
+
+	// Only the first bytes of the file are read to detect the content type,
+	// the file itself is streamed from disk on every attempt.
+	mime := request.ContentType
+	if mime == "" {
+		if mime, _, err = mimetype.DetectFile(request.Filename); err != nil {
+			return nil, fmt.Errorf("%s, file read failed: %w", desc, err)
+		}
+	}
+
+	open := func() (io.ReadCloser, int64, error) {
+		body, size, err := openFile(request.Filename, request.Progress)
+		if err != nil {
+			return nil, 0, fmt.Errorf("file read failed: %w", err)
+		}
+		return body, size, nil
+	}
+
+	// An upload interrupted in transit may have left a complete or a partial ("starter") asset
+	// behind. Reuse the former and delete the latter before sending the file again.
+	var existing *Asset
+	beforeReplay := func() (bool, error) {
+		asset, err := c.findAsset(owner, repo, releaseId, name)
+		if err != nil || asset == nil {
+			return err == nil, err
+		}
+		if asset.State == "uploaded" {
+			existing = asset
+			return false, nil
+		}
+		return true, c.DeleteAsset(owner, repo, asset.Id)
+	}
+
+	var asset = Asset{}
+	_, err = c.do(&rawRequest{url: uri, method: http.MethodPost, open: open, mime: mime, beforeReplay: beforeReplay}, &asset)
+	if existing != nil {
+		c.logger.Infof("%s: asset %s already uploaded", desc, existing.Name)
+		return existing, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", desc, err)
+	}
+
+	return &asset, nil
+}
+
+func (c *Client) findAsset(owner string, repo string, releaseId int64, name string) (*Asset, error) {
+
+	it := c.Assets(owner, repo, releaseId, &ListOptions{PerPage: 100})
+	for it.Next() {
+		if it.Asset().Name == name {
+			return it.Asset(), nil
+		}
+	}
+
+	return nil, it.Err()
+}
+
+func (c *Client) DeleteAsset(owner string, repo string, assetId int64) error {
+
+	desc := "delete a release asset"
+	url := fmt.Sprintf("%s/repos/%s/%s/releases/assets/%d", c.baseURL, owner, repo, assetId)
+
+	err := validate(map[string]string{
+		"user":  owner,
+		"repo":  repo,
+		"token": c.token,
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", desc, err)
+	}
+
+	_, err = c.SendRequest(url, http.MethodDelete, nil, "", nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", desc, err)
+	}
+
+	return nil
+}
+
+// RequestEditAsset renames an asset or changes its label, an empty label is kept.
+type RequestEditAsset struct {
+	Name  string `json:"name"`
+	Label string `json:"label,omitempty"`
+}
+
+// EditAsset changes the name and label of an asset, e.g. to give an uploaded replacement the name of the asset
+// it replaces.
+func (c *Client) EditAsset(owner string, repo string, assetId int64, request *RequestEditAsset) (*Asset, error) {
+
+	desc := "edit a release asset"
+	url := fmt.Sprintf("%s/repos/%s/%s/releases/assets/%d", c.baseURL, owner, repo, assetId)
+
+	err := validate(map[string]string{
+		"user":  owner,
+		"repo":  repo,
+		"token": c.token,
+		"name":  request.Name,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", desc, err)
+	}
+
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", desc, err)
+	}
+
+	var asset = Asset{}
+	_, err = c.do(&rawRequest{url: url, method: http.MethodPatch, body: body}, &asset)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", desc, err)
+	}
+
+	return &asset, nil
+}
+
+// ErrSkipped is the error of uploads not started because an earlier upload failed.
+var ErrSkipped = errors.New("skipped after an earlier failure")
+
+// UploadResult is the outcome of a single file of UploadReleaseAssets.
+type UploadResult struct {
+	Request  *RequestUploadAsset
+	Asset    *Asset
+	Err      error
+	Duration time.Duration
+}
+
+// UploadReleaseAssets uploads the files to the release with at most parallel uploads at a time.
+// Unless keepGoing is set, uploads not started yet are skipped with ErrSkipped once one fails.
+// The results are in the order of the requests.
+func (c *Client) UploadReleaseAssets(owner string, repo string, release *Release, requests []*RequestUploadAsset,
+	parallel int, keepGoing bool) []UploadResult {
+
+	if parallel < 1 {
+		parallel = 1
+	}
+
+	results := make([]UploadResult, len(requests))
+	jobs := make(chan int)
+
+	var failed int32
+	var wg sync.WaitGroup
+
+	for w := 0; w < parallel; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for i := range jobs {
+				result := &results[i]
+				result.Request = requests[i]
+
+				if !keepGoing && atomic.LoadInt32(&failed) > 0 {
+					result.Err = ErrSkipped
+					continue
+				}
+
+				start := time.Now()
+				result.Asset, result.Err = c.UploadReleaseAsset(owner, repo, release, requests[i])
+				result.Duration = time.Since(start)
+
+				if result.Err != nil {
+					atomic.StoreInt32(&failed, 1)
+				}
+			}
+		}()
+	}
+
+	for i := range requests {
+		jobs <- i
+	}
+	close(jobs)
+
+	wg.Wait()
+
+	return results
+}
@@ -0,0 +1,295 @@
+package github
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultBaseURL   = "https://api.github.com"
+	DefaultUploadURL = "https://uploads.github.com"
+)
+
+// Logger is the minimal logging interface used by Client.
+// *logrus.Logger and *logrus.Entry satisfy it.
+type Logger interface {
+	Debugf(format string, args ...interface{})
+	Infof(format string, args ...interface{})
+}
+
+type nopLogger struct{}
+
+func (nopLogger) Debugf(format string, args ...interface{}) {}
+func (nopLogger) Infof(format string, args ...interface{})  {}
+
+// Options configures a Client. Zero values fall back to the public github.com endpoints,
+// http.DefaultClient and a logger that discards everything.
+type Options struct {
+	BaseURL    string       // API endpoint, e.g. https://api.github.com or https://github.example.com/api/v3
+	UploadURL  string       // Upload endpoint overriding the upload_url of releases, e.g. https://github.example.com/api/uploads
+	Token      string       // OAuth token, required for mutating requests.
+	HTTPClient *http.Client // Client used to send requests.
+	Logger     Logger       // Receives debug output of every request.
+	NoWait     bool         // Fail with a *RateLimitError instead of sleeping until the rate limit resets.
+	Retry      RetryPolicy  // Retries of transient failures.
+	DryRun     bool         // Print mutating requests to DryRunOutput instead of sending them, see dryRunTransport.
+	// DryRunOutput receives the requests of a dry run. Default: os.Stderr.
+	DryRunOutput io.Writer
+}
+
+// Client talks to a single GitHub (or GitHub Enterprise) host.
+type Client struct {
+	baseURL    string
+	uploadURL  string
+	uploadSet  bool // uploadURL was configured explicitly rather than derived.
+	token      string
+	httpClient *http.Client
+	logger     Logger
+	noWait     bool
+	retry      RetryPolicy
+
+	rateMu sync.Mutex
+	rate   Rate
+}
+
+func NewClient(opts Options) *Client {
+
+	baseURL, uploadURL := endpoints(opts.BaseURL, opts.UploadURL)
+
+	c := &Client{
+		baseURL:    baseURL,
+		uploadURL:  uploadURL,
+		uploadSet:  opts.UploadURL != "",
+		token:      opts.Token,
+		httpClient: opts.HTTPClient,
+		logger:     opts.Logger,
+		noWait:     opts.NoWait,
+		retry:      opts.Retry.withDefaults(),
+	}
+
+	if c.httpClient == nil {
+		c.httpClient = http.DefaultClient
+	}
+
+	if opts.DryRun {
+		out := opts.DryRunOutput
+		if out == nil {
+			out = os.Stderr
+		}
+		httpClient := *c.httpClient
+		httpClient.Transport = newDryRunTransport(httpClient.Transport, out)
+		c.httpClient = &httpClient
+	}
+	if c.logger == nil {
+		c.logger = nopLogger{}
+	}
+
+	return c
+}
+
+// SendRequest sends the request and decodes a JSON response body into v when v is not nil,
+// a *[]byte v receives the raw body instead.
+// Responses with a 4xx or 5xx status code are returned along with an *APIError.
+// Rate limited requests are retried once the limit resets, unless the client was created with NoWait,
+// and transient failures of idempotent methods are retried according to the retry policy.
+func (c *Client) SendRequest(url string, method string, body []byte, mime string, v interface{}) (*http.Response, error) {
+	return c.do(&rawRequest{url: url, method: method, body: body, mime: mime}, v)
+}
+
+type rawRequest struct {
+	url    string
+	method string
+	body   []byte
+	mime   string
+	header http.Header // Additional request headers, e.g. Accept or Range.
+
+	// open, when set, replaces body with a stream of known length, e.g. a file.
+	// It is called again for every attempt.
+	open func() (io.ReadCloser, int64, error)
+
+	// beforeReplay is consulted before retrying a method the retry policy does not consider
+	// idempotent. It reports whether the request may be sent again; without it such requests
+	// are never retried.
+	beforeReplay func() (bool, error)
+}
+
+func (c *Client) do(r *rawRequest, v interface{}) (*http.Response, error) {
+
+	var resp *http.Response
+	var data []byte
+	var err error
+
+	for attempt, waits := 1, 0; ; {
+
+		if err = c.waitRateLimit(); err != nil {
+			return nil, err
+		}
+
+		resp, data, err = c.send(r)
+
+		if err == nil {
+			if limited := rateLimited(resp, data, time.Now()); limited != nil {
+				if waits++; waits > maxRateLimitWaits {
+					return resp, limited
+				}
+				if err = c.sleepRateLimit(limited); err != nil {
+					return resp, err
+				}
+				continue
+			}
+		}
+
+		if attempt >= c.retry.MaxAttempts || !c.retry.transient(resp, err) {
+			break
+		}
+
+		if !c.retry.idempotent(r.method) && r.beforeReplay == nil {
+			break
+		}
+
+		delay := c.retry.delay(attempt)
+		if err != nil {
+			c.logger.Infof("%s %s failed: %v, retry %d/%d in %v", r.method, r.url, err, attempt, c.retry.MaxAttempts-1, delay)
+		} else {
+			c.logger.Infof("%s %s failed: %s, retry %d/%d in %v", r.method, r.url, resp.Status, attempt, c.retry.MaxAttempts-1, delay)
+		}
+		time.Sleep(delay)
+
+		if !c.retry.idempotent(r.method) {
+			replay, replayErr := r.beforeReplay()
+			if replayErr != nil {
+				c.logger.Infof("%s %s not replayed: %v", r.method, r.url, replayErr)
+			}
+			if replayErr != nil || !replay {
+				break
+			}
+		}
+
+		attempt++
+	}
+
+	if err != nil {
+		return resp, err
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return resp, newAPIError(resp, data)
+	}
+
+	if raw, ok := v.(*[]byte); ok {
+		*raw = data
+		return resp, nil
+	}
+
+	if len(data) > 0 && v != nil {
+		err = json.Unmarshal(data, v)
+		if err != nil {
+			return resp, fmt.Errorf("json.Unmarshal failed: %w", err)
+		}
+	}
+
+	return resp, nil
+}
+
+// send performs a single round trip and returns the response with its body fully read.
+func (c *Client) send(r *rawRequest) (*http.Response, []byte, error) {
+
+	resp, err := c.roundTrip(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	//noinspection GoUnhandledErrorResult
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(resp.Body)
+
+	c.logger.Debugf("Body: %s", string(data))
+
+	if err != nil {
+		return resp, nil, fmt.Errorf("ioutil.ReadAll failed: %w", err)
+	}
+
+	return resp, data, nil
+}
+
+// roundTrip performs a single round trip, the caller must close the response body.
+func (c *Client) roundTrip(r *rawRequest) (*http.Response, error) {
+
+	var body io.ReadCloser = ioutil.NopCloser(bytes.NewReader(r.body))
+	length := int64(len(r.body))
+
+	if r.open != nil {
+		var err error
+		if body, length, err = r.open(); err != nil {
+			return nil, err
+		}
+	}
+
+	req, err := http.NewRequest(r.method, r.url, body)
+	if err != nil {
+		_ = body.Close()
+		return nil, fmt.Errorf("http.NewRequest failed: %w", err)
+	}
+	req.ContentLength = length
+	if length == 0 {
+		_ = body.Close()
+		req.Body = http.NoBody
+	}
+
+	for k, v := range r.header {
+		req.Header[k] = v
+	}
+
+	mime := r.mime
+	if mime == "" {
+		mime = "application/json"
+	}
+	req.Header.Set("Content-Type", mime)
+
+	if c.token != "" {
+		req.SetBasicAuth(c.token, "x-oauth-basic")
+	}
+
+	c.logger.Debugf("%s %s", r.method, r.url)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("http.Client.Do failed: %w", err)
+	}
+
+	c.logger.Debugf("StatusCode: %v", resp.StatusCode)
+	c.logger.Debugf("Header: %v", resp.Header)
+
+	c.updateRate(resp.Header)
+
+	return resp, nil
+}
+
+// ValidationError is returned before any request is sent when a required argument is missing.
+type ValidationError struct {
+	Field string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s is required", e.Field)
+}
+
+func validate(input map[string]string) error {
+
+	for k, v := range input {
+
+		if v == "" {
+			return &ValidationError{Field: k}
+		}
+	}
+
+	return nil
+}
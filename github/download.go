@@ -0,0 +1,195 @@
+package github
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+)
+
+// partialSuffix is appended to the path of a download in progress.
+const partialSuffix = ".part"
+
+type RequestDownloadAsset struct {
+	Path     string       // Required. Local file to write. An interrupted download left in Path + ".part" is resumed.
+	Progress ProgressFunc // Optional. Reports the download progress, including bytes resumed from disk.
+
+	// Verify is called with the path of the complete partial file before it is renamed to Path.
+	// When it fails the partial file is removed and Path is left untouched.
+	Verify func(path string) error
+}
+
+// DownloadAsset downloads the asset through the asset API with Accept: application/octet-stream,
+// so the token grants access to assets of private repositories. The file is written to
+// Path + ".part" and renamed to Path once complete; interrupted downloads resume with a Range request.
+func (c *Client) DownloadAsset(owner string, repo string, asset *Asset, request *RequestDownloadAsset) error {
+
+	desc := "download a release asset"
+
+	err := validate(map[string]string{
+		"user": owner,
+		"repo": repo,
+		"path": request.Path,
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", desc, err)
+	}
+
+	url := asset.Url
+	if url == "" {
+		url = fmt.Sprintf("%s/repos/%s/%s/releases/assets/%d", c.baseURL, owner, repo, asset.Id)
+	}
+
+	part := request.Path + partialSuffix
+
+	// Like do, rate limits are waited for without using up an attempt.
+	for attempt, waits := 1, 0; ; {
+
+		if err = c.waitRateLimit(); err != nil {
+			return fmt.Errorf("%s: %w", desc, err)
+		}
+
+		err = c.download(url, part, asset.Size, request.Progress)
+		if err == nil {
+			break
+		}
+
+		var limited *RateLimitError
+		if errors.As(err, &limited) {
+			if waits++; waits > maxRateLimitWaits {
+				return fmt.Errorf("%s: %w", desc, err)
+			}
+			if err = c.sleepRateLimit(limited); err != nil {
+				return fmt.Errorf("%s: %w", desc, err)
+			}
+			continue
+		}
+
+		if attempt >= c.retry.MaxAttempts || !c.retry.transientError(err) {
+			return fmt.Errorf("%s: %w", desc, err)
+		}
+
+		delay := c.retry.delay(attempt)
+		c.logger.Infof("%s %s failed: %v, retry %d/%d in %v", desc, asset.Name, err, attempt, c.retry.MaxAttempts-1, delay)
+		time.Sleep(delay)
+
+		attempt++
+	}
+
+	if request.Verify != nil {
+		if err = request.Verify(part); err != nil {
+			_ = os.Remove(part)
+			return fmt.Errorf("%s: %w", desc, err)
+		}
+	}
+
+	if err = os.Rename(part, request.Path); err != nil {
+		return fmt.Errorf("%s: %w", desc, err)
+	}
+
+	return nil
+}
+
+// FetchAsset returns the content of a small asset, e.g. a checksum manifest or a signature.
+func (c *Client) FetchAsset(owner string, repo string, asset *Asset) ([]byte, error) {
+
+	desc := "fetch a release asset"
+
+	url := asset.Url
+	if url == "" {
+		url = fmt.Sprintf("%s/repos/%s/%s/releases/assets/%d", c.baseURL, owner, repo, asset.Id)
+	}
+
+	header := http.Header{}
+	header.Set("Accept", "application/octet-stream")
+
+	var data []byte
+	_, err := c.do(&rawRequest{url: url, method: http.MethodGet, header: header}, &data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", desc, err)
+	}
+
+	return data, nil
+}
+
+// download appends the missing bytes of the asset to the partial file.
+func (c *Client) download(url string, part string, size int64, progress ProgressFunc) error {
+
+	var offset int64
+	if info, err := os.Stat(part); err == nil {
+		offset = info.Size()
+	}
+
+	if offset > size {
+		offset = 0
+	}
+	if offset == size && size > 0 {
+		return nil
+	}
+
+	header := http.Header{}
+	header.Set("Accept", "application/octet-stream")
+	if offset > 0 {
+		header.Set("Range", "bytes="+strconv.FormatInt(offset, 10)+"-")
+		c.logger.Debugf("resume %s from byte %d", part, offset)
+	}
+
+	resp, err := c.roundTrip(&rawRequest{url: url, method: http.MethodGet, header: header})
+	if err != nil {
+		return err
+	}
+
+	//noinspection GoUnhandledErrorResult
+	defer resp.Body.Close()
+
+	flag := os.O_CREATE | os.O_WRONLY
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+		flag |= os.O_APPEND
+	case http.StatusOK:
+		flag |= os.O_TRUNC
+		offset = 0
+	case http.StatusRequestedRangeNotSatisfiable:
+		// The partial file does not belong to this asset, start over.
+		if err = os.Remove(part); err != nil {
+			return err
+		}
+		return c.download(url, part, size, progress)
+	default:
+		data, _ := ioutil.ReadAll(resp.Body)
+		if limited := rateLimited(resp, data, time.Now()); limited != nil {
+			return limited
+		}
+		return newAPIError(resp, data)
+	}
+
+	file, err := os.OpenFile(part, flag, 0644)
+	if err != nil {
+		return err
+	}
+
+	var body io.ReadCloser = resp.Body
+	if progress != nil {
+		progress(offset, size)
+		body = &progressReader{ReadCloser: resp.Body, transferred: offset, total: size, progress: progress}
+	}
+
+	n, err := io.Copy(file, body)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("download interrupted after %d bytes: %w", offset+n, err)
+	}
+
+	if size > 0 && offset+n != size {
+		// Retried like a connection closed early, resuming from where it stopped.
+		return fmt.Errorf("download incomplete: %d of %d bytes: %w", offset+n, size, io.ErrUnexpectedEOF)
+	}
+
+	return nil
+}
@@ -0,0 +1,233 @@
+package github
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+const downloadContent = "0123456789abcdefghijklmnopqrstuvwxyz"
+
+// downloadServer serves downloadContent, answering the requests in turn with the handlers and the last one
+// once they are used up. It records the Range header of every request.
+func downloadServer(ranges *[]string, handlers ...http.HandlerFunc) *httptest.Server {
+
+	var served int
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		*ranges = append(*ranges, r.Header.Get("Range"))
+
+		handler := handlers[len(handlers)-1]
+		if served < len(handlers) {
+			handler = handlers[served]
+		}
+		served++
+
+		handler(w, r)
+	}))
+}
+
+// serveRange answers a Range request with the rest of downloadContent, other requests with all of it.
+func serveRange(w http.ResponseWriter, r *http.Request) {
+
+	offset, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(r.Header.Get("Range"), "bytes="), "-"))
+	if err != nil {
+		_, _ = w.Write([]byte(downloadContent))
+		return
+	}
+
+	w.WriteHeader(http.StatusPartialContent)
+	_, _ = w.Write([]byte(downloadContent[offset:]))
+}
+
+// serveHalf sends half of downloadContent without a Content-Length, so the copy ends without an error.
+func serveHalf(w http.ResponseWriter, r *http.Request) {
+	_, _ = w.Write([]byte(downloadContent[:len(downloadContent)/2]))
+	w.(http.Flusher).Flush()
+}
+
+func serveStatus(status int, header ...string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		for i := 0; i < len(header); i += 2 {
+			w.Header().Set(header[i], header[i+1])
+		}
+		w.WriteHeader(status)
+	}
+}
+
+func TestDownloadAsset(t *testing.T) {
+
+	tests := []struct {
+		name       string
+		partial    string
+		handlers   []http.HandlerFunc
+		noWait     bool
+		wantRanges []string
+		wantErr    bool
+	}{
+		{
+			name:       "complete",
+			handlers:   []http.HandlerFunc{serveRange},
+			wantRanges: []string{""},
+		},
+		{
+			name:       "resume a partial file",
+			partial:    downloadContent[:10],
+			handlers:   []http.HandlerFunc{serveRange},
+			wantRanges: []string{"bytes=10-"},
+		},
+		{
+			name:       "range ignored",
+			partial:    "xxxxxxxxxx",
+			handlers:   []http.HandlerFunc{func(w http.ResponseWriter, r *http.Request) { _, _ = w.Write([]byte(downloadContent)) }},
+			wantRanges: []string{"bytes=10-"},
+		},
+		{
+			name:       "range not satisfiable",
+			partial:    "xxxxxxxxxx",
+			handlers:   []http.HandlerFunc{serveStatus(http.StatusRequestedRangeNotSatisfiable), serveRange},
+			wantRanges: []string{"bytes=10-", ""},
+		},
+		{
+			name:       "partial file longer than the asset",
+			partial:    downloadContent + "xx",
+			handlers:   []http.HandlerFunc{serveRange},
+			wantRanges: []string{""},
+		},
+		{
+			name:       "incomplete download resumed",
+			handlers:   []http.HandlerFunc{serveHalf, serveRange},
+			wantRanges: []string{"", "bytes=18-"},
+		},
+		{
+			name:       "incomplete download given up",
+			handlers:   []http.HandlerFunc{serveHalf, serveStatus(http.StatusServiceUnavailable)},
+			wantRanges: []string{"", "bytes=18-", "bytes=18-"},
+			wantErr:    true,
+		},
+		{
+			name:       "rate limited",
+			handlers:   []http.HandlerFunc{serveStatus(http.StatusTooManyRequests, "Retry-After", "0"), serveRange},
+			wantRanges: []string{"", ""},
+		},
+		{
+			name:       "rate limited without waiting",
+			handlers:   []http.HandlerFunc{serveStatus(http.StatusTooManyRequests, "Retry-After", "60"), serveRange},
+			noWait:     true,
+			wantRanges: []string{""},
+			wantErr:    true,
+		},
+		{
+			name:       "not found",
+			handlers:   []http.HandlerFunc{serveStatus(http.StatusNotFound)},
+			wantRanges: []string{""},
+			wantErr:    true,
+		},
+	}
+
+	for _, test := range tests {
+
+		dir, err := ioutil.TempDir("", "download")
+		if err != nil {
+			t.Fatal(err)
+		}
+		path := filepath.Join(dir, "asset.bin")
+
+		if test.partial != "" {
+			if err = ioutil.WriteFile(path+partialSuffix, []byte(test.partial), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		var ranges []string
+		server := downloadServer(&ranges, test.handlers...)
+		c := NewClient(Options{
+			BaseURL: server.URL,
+			NoWait:  test.noWait,
+			Retry:   RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, Jitter: Float64(0)},
+		})
+
+		asset := &Asset{Url: server.URL + "/asset", Name: "asset.bin", Size: int64(len(downloadContent))}
+		err = c.DownloadAsset("o", "r", asset, &RequestDownloadAsset{Path: path})
+		server.Close()
+
+		if strings.Join(ranges, ",") != strings.Join(test.wantRanges, ",") {
+			t.Errorf("%s: ranges %q, want %q", test.name, ranges, test.wantRanges)
+		}
+
+		if test.wantErr {
+			if err == nil {
+				t.Errorf("%s: DownloadAsset succeeded, want an error", test.name)
+			}
+			if _, statErr := os.Stat(path); statErr == nil {
+				t.Errorf("%s: %s written despite the error", test.name, path)
+			}
+		} else {
+			data, readErr := ioutil.ReadFile(path)
+			if err != nil || readErr != nil || string(data) != downloadContent {
+				t.Errorf("%s: DownloadAsset = %v, content %q, %v", test.name, err, data, readErr)
+			}
+		}
+
+		_ = os.RemoveAll(dir)
+	}
+}
+
+func TestDownloadAssetRateLimitError(t *testing.T) {
+
+	var ranges []string
+	server := downloadServer(&ranges, serveStatus(http.StatusTooManyRequests, "Retry-After", "60"))
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "download")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	c := NewClient(Options{BaseURL: server.URL, NoWait: true})
+	asset := &Asset{Url: server.URL + "/asset", Size: 1}
+	err = c.DownloadAsset("o", "r", asset, &RequestDownloadAsset{Path: filepath.Join(dir, "a")})
+
+	var limited *RateLimitError
+	if !errors.As(err, &limited) || !limited.Secondary || limited.RetryAfter != time.Minute {
+		t.Errorf("DownloadAsset = %v, want a secondary *RateLimitError retrying after 1m", err)
+	}
+}
+
+func TestDownloadAssetVerify(t *testing.T) {
+
+	var ranges []string
+	server := downloadServer(&ranges, serveRange)
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "download")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "asset.bin")
+	c := NewClient(Options{BaseURL: server.URL})
+	asset := &Asset{Url: server.URL + "/asset", Size: int64(len(downloadContent))}
+
+	err = c.DownloadAsset("o", "r", asset, &RequestDownloadAsset{Path: path, Verify: func(string) error {
+		return errors.New("checksum mismatch")
+	}})
+	if err == nil {
+		t.Fatal("DownloadAsset succeeded, want the error of Verify")
+	}
+
+	for _, p := range []string{path, path + partialSuffix} {
+		if _, statErr := os.Stat(p); statErr == nil {
+			t.Errorf("%s left after a failed verification", p)
+		}
+	}
+}
@@ -0,0 +1,241 @@
+package github
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// syntheticRelease matches the URLs of releases made up by a dry run, which have negative ids.
+var syntheticRelease = regexp.MustCompile(`/releases/(-\d+)(/assets)?$`)
+
+// dryRunTransport sends GET and HEAD requests, and prints all other requests instead of sending them,
+// answering them with made up successful responses. Releases and assets it makes up have negative ids;
+// GET requests for those releases are answered from memory.
+type dryRunTransport struct {
+	base http.RoundTripper
+	out  io.Writer
+
+	mu       sync.Mutex
+	lastId   int64
+	releases map[int64]map[string]interface{}
+	assets   map[int64]map[string]interface{}
+}
+
+func newDryRunTransport(base http.RoundTripper, out io.Writer) *dryRunTransport {
+
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	return &dryRunTransport{
+		base:     base,
+		out:      out,
+		releases: map[int64]map[string]interface{}{},
+		assets:   map[int64]map[string]interface{}{},
+	}
+}
+
+func (t *dryRunTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+
+	if req.Method == http.MethodGet || req.Method == http.MethodHead {
+		if m := syntheticRelease.FindStringSubmatch(req.URL.Path); m != nil {
+			return t.get(req, m[1], m[2] != "")
+		}
+		return t.base.RoundTrip(req)
+	}
+
+	body, size, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	t.print(req, body, size)
+
+	if req.Method == http.MethodPatch {
+		return t.patch(req, body)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	switch {
+	case req.Method == http.MethodDelete:
+		return response(req, http.StatusNoContent, nil)
+
+	case req.Method == http.MethodPost && strings.HasSuffix(req.URL.Path, "/releases"):
+		release := map[string]interface{}{}
+		_ = json.Unmarshal(body, &release)
+		release["id"] = t.nextId()
+		release["upload_url"] = ""
+		t.releases[release["id"].(int64)] = release
+		return response(req, http.StatusCreated, release)
+
+	case req.Method == http.MethodPost:
+		query := req.URL.Query()
+		asset := map[string]interface{}{
+			"id":           t.nextId(),
+			"name":         query.Get("name"),
+			"label":        query.Get("label"),
+			"content_type": req.Header.Get("Content-Type"),
+			"size":         size,
+			"state":        "uploaded",
+		}
+		t.assets[asset["id"].(int64)] = asset
+		return response(req, http.StatusCreated, asset)
+	}
+
+	return response(req, http.StatusOK, map[string]interface{}{})
+}
+
+// patch answers a PATCH of a release or an asset with it changed by the body. Made up ones are changed in
+// memory, others are fetched through the base transport, and an error fetching them is the answer.
+func (t *dryRunTransport) patch(req *http.Request, body []byte) (*http.Response, error) {
+
+	made := t.releases
+	if strings.Contains(req.URL.Path, "/releases/assets/") {
+		made = t.assets
+	}
+	id, _ := strconv.ParseInt(req.URL.Path[strings.LastIndex(req.URL.Path, "/")+1:], 10, 64)
+
+	if id < 0 {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+
+		existing, ok := made[id]
+		if !ok {
+			return response(req, http.StatusNotFound, map[string]string{"message": "Not Found"})
+		}
+		_ = json.Unmarshal(body, &existing)
+		return response(req, http.StatusOK, existing)
+	}
+
+	get, err := http.NewRequest(http.MethodGet, req.URL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	get = get.WithContext(req.Context())
+	get.Header = req.Header.Clone()
+	get.Header.Del("Content-Type")
+
+	resp, err := t.base.RoundTrip(get)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		return resp, err
+	}
+
+	//noinspection GoUnhandledErrorResult
+	defer resp.Body.Close()
+
+	existing := map[string]interface{}{}
+	if err = json.NewDecoder(resp.Body).Decode(&existing); err != nil {
+		return nil, err
+	}
+	_ = json.Unmarshal(body, &existing)
+
+	return response(req, http.StatusOK, existing)
+}
+
+// readBody reads a JSON body, which is printed and answered, and only counts the bytes of any other body,
+// e.g. an asset that may be larger than the memory.
+func readBody(req *http.Request) ([]byte, int64, error) {
+
+	if req.Body == nil {
+		return nil, 0, nil
+	}
+	defer func() { _ = req.Body.Close() }()
+
+	if strings.HasPrefix(req.Header.Get("Content-Type"), "application/json") {
+		body, err := ioutil.ReadAll(req.Body)
+		return body, int64(len(body)), err
+	}
+
+	size, err := io.Copy(ioutil.Discard, req.Body)
+	return nil, size, err
+}
+
+func (t *dryRunTransport) nextId() int64 {
+	t.lastId--
+	return t.lastId
+}
+
+// get answers a GET request of a made up release, which has no assets.
+func (t *dryRunTransport) get(req *http.Request, id string, assets bool) (*http.Response, error) {
+
+	if assets {
+		return response(req, http.StatusOK, []interface{}{})
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	releaseId, _ := strconv.ParseInt(id, 10, 64)
+	release, ok := t.releases[releaseId]
+	if !ok {
+		return response(req, http.StatusNotFound, map[string]string{"message": "Not Found"})
+	}
+
+	return response(req, http.StatusOK, release)
+}
+
+// print writes the request with its credentials redacted and a JSON body indented.
+func (t *dryRunTransport) print(req *http.Request, body []byte, size int64) {
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "dry-run: %s %s\n", req.Method, req.URL)
+
+	var names []string
+	for name := range req.Header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		value := strings.Join(req.Header[name], ", ")
+		if name == "Authorization" {
+			value = "<redacted>"
+		}
+		fmt.Fprintf(&b, "  %s: %s\n", name, value)
+	}
+
+	var indented bytes.Buffer
+	switch {
+	case size == 0:
+	case json.Indent(&indented, body, "  ", "  ") == nil:
+		fmt.Fprintf(&b, "  %s\n", indented.String())
+	default:
+		fmt.Fprintf(&b, "  <%d bytes of %s>\n", size, req.Header.Get("Content-Type"))
+	}
+
+	_, _ = t.out.Write(b.Bytes())
+}
+
+func response(req *http.Request, status int, v interface{}) (*http.Response, error) {
+
+	var data []byte
+	if v != nil {
+		var err error
+		if data, err = json.Marshal(v); err != nil {
+			return nil, err
+		}
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": {"application/json; charset=utf-8"}},
+		Body:          ioutil.NopCloser(bytes.NewReader(data)),
+		ContentLength: int64(len(data)),
+		Request:       req,
+	}, nil
+}
@@ -0,0 +1,155 @@
+package github
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// readOnlyServer serves a release 7 with an asset 9 and fails the test on any request but GET.
+func readOnlyServer(t *testing.T) *httptest.Server {
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		if r.Method != http.MethodGet {
+			t.Errorf("%s %s sent in a dry run", r.Method, r.URL.Path)
+		}
+
+		switch r.URL.Path {
+		case "/api/v3/repos/o/r/releases/7":
+			_, _ = w.Write([]byte(`{"id":7,"tag_name":"v1.0.0","name":"One","body":"notes","draft":true,"prerelease":true}`))
+		case "/api/v3/repos/o/r/releases/assets/9":
+			_, _ = w.Write([]byte(`{"id":9,"name":"a.bin.tmp","label":"A","size":5,"state":"uploaded"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"Not Found"}`))
+		}
+	}))
+}
+
+func TestDryRunEditRelease(t *testing.T) {
+
+	server := readOnlyServer(t)
+	defer server.Close()
+
+	var out bytes.Buffer
+	c := NewClient(Options{BaseURL: server.URL, Token: "secret", DryRun: true, DryRunOutput: &out})
+
+	release, err := c.UpdateRelease("o", "r", 7, &RequestEditRelease{Draft: Bool(false)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The fields the request leaves out are those of the release.
+	if release.Id != 7 || release.TagName != "v1.0.0" || release.Name != "One" || release.Body != "notes" ||
+		release.Draft || !release.Prerelease {
+		t.Errorf("UpdateRelease = %+v, want release 7 published with its other fields", release)
+	}
+
+	printed := out.String()
+	if !strings.Contains(printed, "dry-run: PATCH "+server.URL+"/api/v3/repos/o/r/releases/7") ||
+		!strings.Contains(printed, `"draft": false`) {
+		t.Errorf("printed\n%s\nwant the PATCH of release 7", printed)
+	}
+	if strings.Contains(printed, "secret") {
+		t.Errorf("printed\n%s\nwith the token", printed)
+	}
+}
+
+func TestDryRunEditAsset(t *testing.T) {
+
+	server := readOnlyServer(t)
+	defer server.Close()
+
+	c := NewClient(Options{BaseURL: server.URL, Token: "secret", DryRun: true, DryRunOutput: ioutil.Discard})
+
+	asset, err := c.EditAsset("o", "r", 9, &RequestEditAsset{Name: "a.bin"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if asset.Id != 9 || asset.Name != "a.bin" || asset.Size != 5 || asset.State != "uploaded" {
+		t.Errorf("EditAsset = %+v, want asset 9 renamed to a.bin", asset)
+	}
+}
+
+func TestDryRunEditMissing(t *testing.T) {
+
+	server := readOnlyServer(t)
+	defer server.Close()
+
+	c := NewClient(Options{BaseURL: server.URL, Token: "secret", DryRun: true, DryRunOutput: ioutil.Discard})
+
+	_, err := c.UpdateRelease("o", "r", 8, &RequestEditRelease{Draft: Bool(false)})
+
+	var apiError *APIError
+	if !errors.As(err, &apiError) || apiError.StatusCode != http.StatusNotFound {
+		t.Errorf("UpdateRelease of a missing release = %v, want a 404 APIError", err)
+	}
+}
+
+func TestDryRunMadeUp(t *testing.T) {
+
+	server := readOnlyServer(t)
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "dryrun")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "a.bin")
+	if err = ioutil.WriteFile(path, []byte("12345"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	c := NewClient(Options{BaseURL: server.URL, Token: "secret", DryRun: true, DryRunOutput: ioutil.Discard})
+
+	release, err := c.CreateRelease("o", "r", &RequestCreateRelease{TagName: "v2.0.0", Name: "Two", Draft: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if release.Id >= 0 || release.TagName != "v2.0.0" {
+		t.Fatalf("CreateRelease = %+v, want a made up release with a negative id", release)
+	}
+
+	published, err := c.UpdateRelease("o", "r", release.Id, &RequestEditRelease{Draft: Bool(false)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if published.Id != release.Id || published.Name != "Two" || published.Draft {
+		t.Errorf("UpdateRelease = %+v, want the made up release published", published)
+	}
+
+	got, err := c.GetRelease("o", "r", release.Id)
+	if err != nil || got.Draft {
+		t.Errorf("GetRelease = %+v, %v, want the made up release published", got, err)
+	}
+
+	asset, err := c.UploadReleaseAsset("o", "r", release, &RequestUploadAsset{Filename: path, Name: "a.bin.tmp"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if asset.Id >= 0 || asset.Size != 5 {
+		t.Fatalf("UploadReleaseAsset = %+v, want a made up asset of 5 bytes", asset)
+	}
+
+	renamed, err := c.EditAsset("o", "r", asset.Id, &RequestEditAsset{Name: "a.bin"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if renamed.Id != asset.Id || renamed.Name != "a.bin" || renamed.Size != 5 {
+		t.Errorf("EditAsset = %+v, want the made up asset renamed", renamed)
+	}
+
+	if err = c.DeleteAsset("o", "r", asset.Id); err != nil {
+		t.Errorf("DeleteAsset = %v", err)
+	}
+}
@@ -0,0 +1,89 @@
+package github
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// GitHub Enterprise Server serves the API below /api/v3 and uploads below /api/uploads
+// of the same host, github.com uses the api. and uploads. sub domains instead.
+const (
+	enterpriseAPIPath    = "/api/v3"
+	enterpriseUploadPath = "/api/uploads"
+)
+
+// endpoints normalizes the configured API and upload endpoints. A base URL other than
+// api.github.com is treated as a GitHub Enterprise Server and gets /api/v3 appended when
+// missing; an empty upload URL is derived from the base URL, a given one is used as is.
+func endpoints(baseURL string, uploadURL string) (string, string) {
+
+	baseURL = strings.TrimRight(baseURL, "/")
+	uploadURL = strings.TrimRight(uploadURL, "/")
+
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+
+	if baseURL == DefaultBaseURL {
+		if uploadURL == "" {
+			uploadURL = DefaultUploadURL
+		}
+		return baseURL, uploadURL
+	}
+
+	if !strings.HasSuffix(baseURL, enterpriseAPIPath) {
+		baseURL += enterpriseAPIPath
+	}
+
+	if uploadURL == "" {
+		uploadURL = strings.TrimSuffix(baseURL, enterpriseAPIPath) + enterpriseUploadPath
+	}
+
+	return baseURL, uploadURL
+}
+
+var uriTemplateExpression = regexp.MustCompile(`\{([?&]?)([^}]*)\}`)
+
+// expandURITemplate expands the subset of RFC 6570 used by GitHub hypermedia links:
+// simple {var} expressions and form style {?var,var} / {&var} query expressions.
+// Variables without a value are left out.
+func expandURITemplate(template string, values map[string]string) string {
+
+	hasQuery := strings.Contains(uriTemplateExpression.ReplaceAllString(template, ""), "?")
+
+	return uriTemplateExpression.ReplaceAllStringFunc(template, func(expression string) string {
+
+		match := uriTemplateExpression.FindStringSubmatch(expression)
+		operator, names := match[1], strings.Split(match[2], ",")
+
+		if operator == "" {
+			return escapeURITemplate(values[names[0]])
+		}
+
+		var pairs []string
+		for _, name := range names {
+			if value := values[name]; value != "" {
+				pairs = append(pairs, escapeURITemplate(name)+"="+escapeURITemplate(value))
+			}
+		}
+
+		if len(pairs) == 0 {
+			return ""
+		}
+
+		prefix := "&"
+		if operator == "?" && !hasQuery {
+			prefix = "?"
+			hasQuery = true
+		}
+
+		return prefix + strings.Join(pairs, "&")
+	})
+}
+
+// escapeURITemplate percent-encodes all but the unreserved characters, as RFC 6570 expands variables.
+func escapeURITemplate(value string) string {
+	// QueryEscape leaves only the unreserved characters as they are, but encodes a space as +.
+	return strings.Replace(url.QueryEscape(value), "+", "%20", -1)
+}
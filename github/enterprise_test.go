@@ -0,0 +1,92 @@
+package github
+
+import (
+	"testing"
+)
+
+func TestEndpoints(t *testing.T) {
+
+	tests := []struct {
+		base       string
+		upload     string
+		wantBase   string
+		wantUpload string
+	}{
+		{"", "", DefaultBaseURL, DefaultUploadURL},
+		{"https://api.github.com/", "", DefaultBaseURL, DefaultUploadURL},
+		{DefaultBaseURL, "https://uploads.example.com", DefaultBaseURL, "https://uploads.example.com"},
+
+		// GitHub Enterprise Server, with or without the API path.
+		{"https://github.example.com", "", "https://github.example.com/api/v3", "https://github.example.com/api/uploads"},
+		{"https://github.example.com/", "", "https://github.example.com/api/v3", "https://github.example.com/api/uploads"},
+		{"https://github.example.com/api/v3", "", "https://github.example.com/api/v3", "https://github.example.com/api/uploads"},
+		{"https://github.example.com/api/v3/", "", "https://github.example.com/api/v3", "https://github.example.com/api/uploads"},
+		{"http://127.0.0.1:8080", "", "http://127.0.0.1:8080/api/v3", "http://127.0.0.1:8080/api/uploads"},
+
+		// An explicit upload endpoint is used as is, e.g. behind a proxy.
+		{"https://github.example.com", "https://uploads.example.com", "https://github.example.com/api/v3", "https://uploads.example.com"},
+		{"https://github.example.com", "https://uploads.example.com/api/uploads/", "https://github.example.com/api/v3", "https://uploads.example.com/api/uploads"},
+		{"https://github.example.com", "https://proxy.example.com/github/uploads", "https://github.example.com/api/v3", "https://proxy.example.com/github/uploads"},
+		{"https://github.example.com", DefaultUploadURL, "https://github.example.com/api/v3", DefaultUploadURL},
+	}
+
+	for _, test := range tests {
+		base, upload := endpoints(test.base, test.upload)
+		if base != test.wantBase || upload != test.wantUpload {
+			t.Errorf("endpoints(%q, %q) = %q, %q, want %q, %q",
+				test.base, test.upload, base, upload, test.wantBase, test.wantUpload)
+		}
+	}
+}
+
+func TestExpandURITemplate(t *testing.T) {
+
+	tests := []struct {
+		template string
+		values   map[string]string
+		want     string
+	}{
+		{
+			"https://uploads.github.com/repos/o/r/releases/1/assets{?name,label}",
+			map[string]string{"name": "app.tar.gz", "label": "App"},
+			"https://uploads.github.com/repos/o/r/releases/1/assets?name=app.tar.gz&label=App",
+		},
+		{
+			"https://uploads.github.com/repos/o/r/releases/1/assets{?name,label}",
+			map[string]string{"name": "app.tar.gz"},
+			"https://uploads.github.com/repos/o/r/releases/1/assets?name=app.tar.gz",
+		},
+		{
+			"https://uploads.github.com/repos/o/r/releases/1/assets{?name,label}",
+			nil,
+			"https://uploads.github.com/repos/o/r/releases/1/assets",
+		},
+		{
+			"https://uploads.github.com/repos/o/r/releases/1/assets{?name,label}",
+			map[string]string{"name": "a b&c+d.zip", "label": "100% done"},
+			"https://uploads.github.com/repos/o/r/releases/1/assets?name=a%20b%26c%2Bd.zip&label=100%25%20done",
+		},
+		// A template with a query already continues it.
+		{
+			"https://github.example.com/api/uploads/assets?x=1{?name}",
+			map[string]string{"name": "a"},
+			"https://github.example.com/api/uploads/assets?x=1&name=a",
+		},
+		{
+			"https://example.com/assets{?name}{&label}",
+			map[string]string{"name": "a", "label": "b"},
+			"https://example.com/assets?name=a&label=b",
+		},
+		{
+			"https://example.com/repos/{owner}/{repo}",
+			map[string]string{"owner": "o", "repo": "a/b c"},
+			"https://example.com/repos/o/a%2Fb%20c",
+		},
+	}
+
+	for _, test := range tests {
+		if got := expandURITemplate(test.template, test.values); got != test.want {
+			t.Errorf("expandURITemplate(%q, %v) = %q, want %q", test.template, test.values, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package github
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// APIError is returned for every response with a 4xx or 5xx status code.
+// Use errors.As to get it from the errors returned by Client methods.
+type APIError struct {
+	StatusCode       int          `json:"-"`                 // HTTP status code of the response.
+	Status           string       `json:"-"`                 // HTTP status line, e.g. "404 Not Found".
+	Message          string       `json:"message"`           // Message of the error, e.g. "Validation Failed".
+	DocumentationURL string       `json:"documentation_url"` // Link to the documentation of the endpoint.
+	Errors           []FieldError `json:"errors"`            // Field level errors of validation failures.
+	RequestID        string       `json:"-"`                 // X-GitHub-Request-Id header, useful when contacting support.
+}
+
+// FieldError describes why a single field of a request was rejected.
+// See https://developer.github.com/v3/#client-errors
+type FieldError struct {
+	Resource string `json:"resource"`
+	Field    string `json:"field"`
+	Code     string `json:"code"` // missing, missing_field, invalid, already_exists or custom
+	Message  string `json:"message"`
+}
+
+// UnmarshalJSON accepts the plain strings some endpoints return instead of error objects.
+func (e *FieldError) UnmarshalJSON(data []byte) error {
+
+	var message string
+	if err := json.Unmarshal(data, &message); err == nil {
+		*e = FieldError{Code: "custom", Message: message}
+		return nil
+	}
+
+	type fieldError FieldError
+	return json.Unmarshal(data, (*fieldError)(e))
+}
+
+func (e FieldError) String() string {
+
+	if e.Message != "" {
+		return e.Message
+	}
+
+	return strings.TrimSpace(fmt.Sprintf("%s %s %s", e.Resource, e.Field, e.Code))
+}
+
+func newAPIError(resp *http.Response, body []byte) *APIError {
+
+	e := &APIError{
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		RequestID:  resp.Header.Get("X-GitHub-Request-Id"),
+	}
+
+	if err := json.Unmarshal(body, e); err != nil {
+		e.Message = strings.TrimSpace(string(body))
+	}
+
+	return e
+}
+
+func (e *APIError) Error() string {
+
+	var b strings.Builder
+
+	b.WriteString(e.Status)
+	if e.Message != "" {
+		b.WriteString(": ")
+		b.WriteString(e.Message)
+	}
+
+	if len(e.Errors) > 0 {
+		var fields []string
+		for _, f := range e.Errors {
+			fields = append(fields, f.String())
+		}
+		fmt.Fprintf(&b, " [%s]", strings.Join(fields, "; "))
+	}
+
+	if e.DocumentationURL != "" {
+		fmt.Fprintf(&b, " (%s)", e.DocumentationURL)
+	}
+
+	return b.String()
+}
+
+// HasCode reports whether any field error carries the code, e.g. "already_exists".
+func (e *APIError) HasCode(code string) bool {
+
+	for _, f := range e.Errors {
+		if f.Code == code {
+			return true
+		}
+	}
+
+	return false
+}
+
+func asAPIError(err error) (*APIError, bool) {
+
+	var e *APIError
+	ok := errors.As(err, &e)
+
+	return e, ok
+}
+
+// IsNotFound reports whether err is a 404 response. GitHub also answers 404
+// for private repositories the token cannot access.
+func IsNotFound(err error) bool {
+	e, ok := asAPIError(err)
+	return ok && e.StatusCode == http.StatusNotFound
+}
+
+// IsUnauthorized reports whether err is caused by missing or bad credentials.
+func IsUnauthorized(err error) bool {
+	e, ok := asAPIError(err)
+	return ok && e.StatusCode == http.StatusUnauthorized
+}
+
+// IsAlreadyExists reports whether err is a validation failure because the resource,
+// e.g. the release of a tag or an asset name, already exists.
+func IsAlreadyExists(err error) bool {
+	e, ok := asAPIError(err)
+	return ok && e.StatusCode == http.StatusUnprocessableEntity && e.HasCode("already_exists")
+}
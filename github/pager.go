@@ -0,0 +1,178 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+)
+
+// ListOptions controls pagination of list requests.
+type ListOptions struct {
+	PerPage int // Results per page (max 100). Default: 30
+	Limit   int // Maximum number of items to return, 0 for no limit.
+}
+
+var linkNextPattern = regexp.MustCompile(`<([^>]+)>\s*;\s*rel="next"`)
+
+// nextPageURL returns the url of the rel="next" entry in a Link header, or an empty string on the last page.
+func nextPageURL(header http.Header) string {
+
+	for _, link := range header["Link"] {
+		if match := linkNextPattern.FindStringSubmatch(link); match != nil {
+			return match[1]
+		}
+	}
+
+	return ""
+}
+
+// pager walks the Link rel="next" chain of a list endpoint one item at a time.
+type pager struct {
+	client *Client
+	desc   string
+	next   string
+	limit  int
+	count  int
+	items  []json.RawMessage
+	err    error
+}
+
+func (c *Client) newPager(desc string, uri string, opts *ListOptions) *pager {
+
+	p := &pager{client: c, desc: desc, next: uri}
+
+	if opts != nil {
+		p.limit = opts.Limit
+
+		if opts.PerPage > 0 {
+			u, err := url.Parse(uri)
+			if err != nil {
+				p.err = fmt.Errorf("%s: %w", desc, err)
+				return p
+			}
+
+			query := u.Query()
+			query.Set("per_page", strconv.Itoa(opts.PerPage))
+			u.RawQuery = query.Encode()
+			p.next = u.String()
+		}
+	}
+
+	return p
+}
+
+// nextItem returns the next raw item, or nil when the listing is exhausted, the limit is reached or an error occurred.
+func (p *pager) nextItem() json.RawMessage {
+
+	if p.err != nil || (p.limit > 0 && p.count >= p.limit) {
+		return nil
+	}
+
+	for len(p.items) == 0 {
+		if p.next == "" {
+			return nil
+		}
+
+		var page []json.RawMessage
+		resp, err := p.client.SendRequest(p.next, http.MethodGet, nil, "", &page)
+		if err != nil {
+			p.err = fmt.Errorf("%s: %w", p.desc, err)
+			return nil
+		}
+
+		p.items = page
+		p.next = nextPageURL(resp.Header)
+	}
+
+	item := p.items[0]
+	p.items = p.items[1:]
+	p.count++
+
+	return item
+}
+
+func (p *pager) decode(item json.RawMessage, v interface{}) bool {
+
+	if err := json.Unmarshal(item, v); err != nil {
+		p.err = fmt.Errorf("%s: %w", p.desc, err)
+		return false
+	}
+
+	return true
+}
+
+// ReleaseIterator iterates over the releases of a repository, fetching pages on demand.
+//
+//	it := client.Releases(owner, repo, nil)
+//	for it.Next() {
+//		release := it.Release()
+//	}
+//	if err := it.Err(); err != nil {
+//	}
+type ReleaseIterator struct {
+	pager   *pager
+	current *Release
+}
+
+// Next advances to the next release and reports whether there is one.
+func (it *ReleaseIterator) Next() bool {
+
+	item := it.pager.nextItem()
+	if item == nil {
+		return false
+	}
+
+	var release = Release{}
+	if !it.pager.decode(item, &release) {
+		return false
+	}
+
+	it.current = &release
+	return true
+}
+
+// Release returns the current release.
+func (it *ReleaseIterator) Release() *Release {
+	return it.current
+}
+
+// Err returns the first error encountered during iteration.
+func (it *ReleaseIterator) Err() error {
+	return it.pager.err
+}
+
+// AssetIterator iterates over the assets of a release, fetching pages on demand.
+type AssetIterator struct {
+	pager   *pager
+	current *Asset
+}
+
+// Next advances to the next asset and reports whether there is one.
+func (it *AssetIterator) Next() bool {
+
+	item := it.pager.nextItem()
+	if item == nil {
+		return false
+	}
+
+	var asset = Asset{}
+	if !it.pager.decode(item, &asset) {
+		return false
+	}
+
+	it.current = &asset
+	return true
+}
+
+// Asset returns the current asset.
+func (it *AssetIterator) Asset() *Asset {
+	return it.current
+}
+
+// Err returns the first error encountered during iteration.
+func (it *AssetIterator) Err() error {
+	return it.pager.err
+}
@@ -0,0 +1,60 @@
+package github
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestNextPageURL(t *testing.T) {
+
+	tests := []struct {
+		links []string
+		want  string
+	}{
+		{nil, ""},
+		{[]string{`<https://api.github.com/repositories/1/releases?page=2>; rel="next", <https://api.github.com/repositories/1/releases?page=5>; rel="last"`},
+			"https://api.github.com/repositories/1/releases?page=2"},
+		{[]string{`<https://api.github.com/repositories/1/releases?page=4>; rel="prev", <https://api.github.com/repositories/1/releases?page=6>; rel="next"`},
+			"https://api.github.com/repositories/1/releases?page=6"},
+		{[]string{`<https://api.github.com/repositories/1/releases?page=1>; rel="first", <https://api.github.com/repositories/1/releases?page=4>; rel="prev"`},
+			""},
+		{[]string{`<https://github.example.com/api/v3/repos/o/r/releases?per_page=100&page=2>;rel="next"`},
+			"https://github.example.com/api/v3/repos/o/r/releases?per_page=100&page=2"},
+		// The entries may come in several headers.
+		{[]string{`<https://api.github.com/x?page=1>; rel="first"`, `<https://api.github.com/x?page=3>; rel="next"`},
+			"https://api.github.com/x?page=3"},
+	}
+
+	for _, test := range tests {
+		header := http.Header{}
+		for _, link := range test.links {
+			header.Add("Link", link)
+		}
+		if got := nextPageURL(header); got != test.want {
+			t.Errorf("nextPageURL(%q) = %q, want %q", test.links, got, test.want)
+		}
+	}
+}
+
+func TestNewPagerPerPage(t *testing.T) {
+
+	c := &Client{}
+
+	tests := []struct {
+		uri  string
+		opts *ListOptions
+		want string
+	}{
+		{"https://api.github.com/repos/o/r/releases", nil, "https://api.github.com/repos/o/r/releases"},
+		{"https://api.github.com/repos/o/r/releases", &ListOptions{Limit: 5}, "https://api.github.com/repos/o/r/releases"},
+		{"https://api.github.com/repos/o/r/releases", &ListOptions{PerPage: 100}, "https://api.github.com/repos/o/r/releases?per_page=100"},
+		{"https://api.github.com/repos/o/r/releases?per_page=10", &ListOptions{PerPage: 50}, "https://api.github.com/repos/o/r/releases?per_page=50"},
+	}
+
+	for _, test := range tests {
+		p := c.newPager("list", test.uri, test.opts)
+		if p.err != nil || p.next != test.want {
+			t.Errorf("newPager(%q, %+v) starts at %q (%v), want %q", test.uri, test.opts, p.next, p.err, test.want)
+		}
+	}
+}
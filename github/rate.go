@@ -0,0 +1,164 @@
+package github
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// maxRateLimitWaits bounds how many times a single request sleeps on a rate limit before giving up.
+const maxRateLimitWaits = 5
+
+// secondaryRateLimitWait is used when a secondary rate limit response carries no Retry-After header.
+const secondaryRateLimitWait = time.Minute
+
+// Rate is the primary rate limit status reported by the X-RateLimit-* response headers.
+type Rate struct {
+	Limit     int       // The maximum number of requests per hour.
+	Remaining int       // The number of requests remaining in the current window.
+	Reset     time.Time // The time at which the current window resets.
+}
+
+// RateLimitError is returned when a request is rate limited and the client is configured not to wait.
+type RateLimitError struct {
+	Rate       Rate
+	RetryAfter time.Duration // How long to wait before retrying.
+	Secondary  bool          // true for secondary (abuse) rate limits.
+}
+
+func (e *RateLimitError) Error() string {
+
+	kind := "rate limit"
+	if e.Secondary {
+		kind = "secondary rate limit"
+	}
+
+	// The quota is unknown when the response had no X-RateLimit headers.
+	if e.Rate.Limit == 0 {
+		return fmt.Sprintf("%s exceeded, retry after %v", kind, e.RetryAfter)
+	}
+
+	if e.Rate.Reset.IsZero() {
+		return fmt.Sprintf("%s exceeded, retry after %v (%d/%d remaining)",
+			kind, e.RetryAfter, e.Rate.Remaining, e.Rate.Limit)
+	}
+
+	return fmt.Sprintf("%s exceeded, retry after %v (%d/%d remaining, resets at %s)",
+		kind, e.RetryAfter, e.Rate.Remaining, e.Rate.Limit, e.Rate.Reset.Format(time.RFC3339))
+}
+
+// parseRate reads the X-RateLimit-* headers, ok is false when they are absent.
+func parseRate(header http.Header) (rate Rate, ok bool) {
+
+	limit, err := strconv.Atoi(header.Get("X-RateLimit-Limit"))
+	if err != nil {
+		return rate, false
+	}
+
+	rate.Limit = limit
+	rate.Remaining, _ = strconv.Atoi(header.Get("X-RateLimit-Remaining"))
+
+	if reset, err := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		rate.Reset = time.Unix(reset, 0)
+	}
+
+	return rate, true
+}
+
+// rateLimited inspects a response and returns a non-nil error describing the
+// rate limit when the request was rejected because of one.
+func rateLimited(resp *http.Response, body []byte, now time.Time) *RateLimitError {
+
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return nil
+	}
+
+	rate, known := parseRate(resp.Header)
+	e := &RateLimitError{Rate: rate}
+
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+		// Only an exhausted primary quota is reported by the X-RateLimit headers.
+		e.RetryAfter = time.Duration(seconds) * time.Second
+		e.Secondary = !known || rate.Remaining > 0
+		return e
+	}
+
+	if bytes.Contains(bytes.ToLower(body), []byte("secondary rate limit")) ||
+		bytes.Contains(bytes.ToLower(body), []byte("abuse detection")) {
+		e.RetryAfter = secondaryRateLimitWait
+		e.Secondary = true
+		return e
+	}
+
+	if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+		e.RetryAfter = untilReset(rate, now)
+		return e
+	}
+
+	return nil
+}
+
+// untilReset returns the time left until the rate limit window resets, with a second of slack.
+func untilReset(rate Rate, now time.Time) time.Duration {
+
+	wait := rate.Reset.Sub(now) + time.Second
+	if wait < 0 {
+		return 0
+	}
+
+	return wait
+}
+
+// Rate returns the rate limit status observed on the most recent response.
+func (c *Client) Rate() Rate {
+
+	c.rateMu.Lock()
+	defer c.rateMu.Unlock()
+
+	return c.rate
+}
+
+func (c *Client) updateRate(header http.Header) {
+
+	rate, ok := parseRate(header)
+	if !ok {
+		return
+	}
+
+	c.rateMu.Lock()
+	c.rate = rate
+	c.rateMu.Unlock()
+
+	c.logger.Debugf("rate limit: %d/%d remaining, resets at %s",
+		rate.Remaining, rate.Limit, rate.Reset.Format(time.RFC3339))
+}
+
+// waitRateLimit blocks until the primary rate limit resets when the quota is known to be exhausted.
+func (c *Client) waitRateLimit() error {
+
+	rate := c.Rate()
+	if rate.Limit == 0 || rate.Remaining > 0 {
+		return nil
+	}
+
+	wait := untilReset(rate, time.Now())
+	if wait == 0 {
+		return nil
+	}
+
+	return c.sleepRateLimit(&RateLimitError{Rate: rate, RetryAfter: wait})
+}
+
+func (c *Client) sleepRateLimit(e *RateLimitError) error {
+
+	if c.noWait {
+		return e
+	}
+
+	c.logger.Infof("%v, waiting", e)
+	time.Sleep(e.RetryAfter)
+
+	return nil
+}
@@ -0,0 +1,146 @@
+package github
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func header(pairs ...string) http.Header {
+
+	h := http.Header{}
+	for i := 0; i < len(pairs); i += 2 {
+		h.Set(pairs[i], pairs[i+1])
+	}
+
+	return h
+}
+
+func TestParseRate(t *testing.T) {
+
+	tests := []struct {
+		header http.Header
+		want   Rate
+		wantOk bool
+	}{
+		{header(), Rate{}, false},
+		{header("X-RateLimit-Limit", "x"), Rate{}, false},
+		{header("X-RateLimit-Limit", "5000", "X-RateLimit-Remaining", "4999", "X-RateLimit-Reset", "1561939200"),
+			Rate{Limit: 5000, Remaining: 4999, Reset: time.Unix(1561939200, 0)}, true},
+		{header("X-RateLimit-Limit", "60", "X-RateLimit-Remaining", "0"), Rate{Limit: 60}, true},
+	}
+
+	for _, test := range tests {
+		rate, ok := parseRate(test.header)
+		if rate != test.want || ok != test.wantOk {
+			t.Errorf("parseRate(%v) = %+v, %v, want %+v, %v", test.header, rate, ok, test.want, test.wantOk)
+		}
+	}
+}
+
+func TestUntilReset(t *testing.T) {
+
+	now := time.Unix(1561939200, 0)
+
+	tests := []struct {
+		reset time.Time
+		want  time.Duration
+	}{
+		{now.Add(time.Minute), time.Minute + time.Second},
+		{now, time.Second},
+		{now.Add(-time.Minute), 0},
+	}
+
+	for _, test := range tests {
+		if wait := untilReset(Rate{Reset: test.reset}, now); wait != test.want {
+			t.Errorf("untilReset(%v) = %v, want %v", test.reset, wait, test.want)
+		}
+	}
+}
+
+func TestRateLimited(t *testing.T) {
+
+	now := time.Unix(1561939200, 0)
+	reset := "1561939260"
+
+	tests := []struct {
+		name          string
+		status        int
+		header        http.Header
+		body          string
+		wantLimited   bool
+		wantSecondary bool
+		wantWait      time.Duration
+		wantMessage   string
+	}{
+		{
+			name:   "success",
+			status: http.StatusOK,
+			header: header("X-RateLimit-Limit", "5000", "X-RateLimit-Remaining", "0", "X-RateLimit-Reset", reset),
+		},
+		{
+			name:   "forbidden",
+			status: http.StatusForbidden,
+			header: header("X-RateLimit-Limit", "5000", "X-RateLimit-Remaining", "10"),
+			body:   `{"message":"Resource not accessible by integration"}`,
+		},
+		{
+			name:        "primary",
+			status:      http.StatusForbidden,
+			header:      header("X-RateLimit-Limit", "5000", "X-RateLimit-Remaining", "0", "X-RateLimit-Reset", reset),
+			wantLimited: true,
+			wantWait:    61 * time.Second,
+			wantMessage: "rate limit exceeded, retry after 1m1s (0/5000 remaining, resets at " +
+				time.Unix(1561939260, 0).Format(time.RFC3339) + ")",
+		},
+		{
+			name:          "retry after with quota left",
+			status:        http.StatusForbidden,
+			header:        header("Retry-After", "30", "X-RateLimit-Limit", "5000", "X-RateLimit-Remaining", "10"),
+			wantLimited:   true,
+			wantSecondary: true,
+			wantWait:      30 * time.Second,
+			wantMessage:   "secondary rate limit exceeded, retry after 30s (10/5000 remaining)",
+		},
+		{
+			name:          "retry after without rate headers",
+			status:        http.StatusTooManyRequests,
+			header:        header("Retry-After", "5"),
+			wantLimited:   true,
+			wantSecondary: true,
+			wantWait:      5 * time.Second,
+			wantMessage:   "secondary rate limit exceeded, retry after 5s",
+		},
+		{
+			name:          "secondary by message",
+			status:        http.StatusForbidden,
+			header:        header("X-RateLimit-Limit", "5000", "X-RateLimit-Remaining", "10"),
+			body:          `{"message":"You have exceeded a secondary rate limit."}`,
+			wantLimited:   true,
+			wantSecondary: true,
+			wantWait:      secondaryRateLimitWait,
+		},
+	}
+
+	for _, test := range tests {
+
+		resp := &http.Response{StatusCode: test.status, Header: test.header}
+		e := rateLimited(resp, []byte(test.body), now)
+
+		if (e != nil) != test.wantLimited {
+			t.Errorf("%s: rateLimited = %v, want limited %v", test.name, e, test.wantLimited)
+			continue
+		}
+		if e == nil {
+			continue
+		}
+
+		if e.Secondary != test.wantSecondary || e.RetryAfter != test.wantWait {
+			t.Errorf("%s: rateLimited = secondary %v, retry after %v, want %v, %v",
+				test.name, e.Secondary, e.RetryAfter, test.wantSecondary, test.wantWait)
+		}
+		if test.wantMessage != "" && e.Error() != test.wantMessage {
+			t.Errorf("%s: Error() = %q, want %q", test.name, e.Error(), test.wantMessage)
+		}
+	}
+}
@@ -1,293 +1,170 @@
 package github
 
 import (
-	"bytes"
 	"encoding/json"
 	"fmt"
-	"github.com/fatih/color"
-	"github.com/gabriel-vasile/mimetype"
-	"github.com/sirupsen/logrus"
-	"github.com/spf13/viper"
-	"github.com/xykong/github-release/utils"
-	"io/ioutil"
 	"net/http"
 	"time"
 )
 
+type User struct {
+	Login             string `json:"login"`
+	Id                int64  `json:"id"`
+	NodeId            string `json:"node_id"`
+	AvatarUrl         string `json:"avatar_url"`
+	GravatarId        string `json:"gravatar_id"`
+	Url               string `json:"url"`
+	HtmlUrl           string `json:"html_url"`
+	FollowersUrl      string `json:"followers_url"`
+	FollowingUrl      string `json:"following_url"`
+	GistsUrl          string `json:"gists_url"`
+	StarredUrl        string `json:"starred_url"`
+	SubscriptionsUrl  string `json:"subscriptions_url"`
+	OrganizationsUrl  string `json:"organizations_url"`
+	ReposUrl          string `json:"repos_url"`
+	EventsUrl         string `json:"events_url"`
+	ReceivedEventsUrl string `json:"received_events_url"`
+	Type              string `json:"type"`
+	SiteAdmin         bool   `json:"site_admin"`
+}
+
 type Release struct {
-	Url             string `json:"url"`
-	AssetsUrl       string `json:"assets_url"`
-	UploadUrl       string `json:"upload_url"`
-	HtmlUrl         string `json:"html_url"`
-	Id              int    `json:"id"`
-	NodeId          string `json:"node_id"`
-	TagName         string `json:"tag_name"`
-	TargetCommitish string `json:"target_commitish"`
-	Name            string `json:"name"`
-	Draft           bool   `json:"draft"`
-	Author          struct {
-		Login             string `json:"login"`
-		Id                int    `json:"id"`
-		NodeId            string `json:"node_id"`
-		AvatarUrl         string `json:"avatar_url"`
-		GravatarId        string `json:"gravatar_id"`
-		Url               string `json:"url"`
-		HtmlUrl           string `json:"html_url"`
-		FollowersUrl      string `json:"followers_url"`
-		FollowingUrl      string `json:"following_url"`
-		GistsUrl          string `json:"gists_url"`
-		StarredUrl        string `json:"starred_url"`
-		SubscriptionsUrl  string `json:"subscriptions_url"`
-		OrganizationsUrl  string `json:"organizations_url"`
-		ReposUrl          string `json:"repos_url"`
-		EventsUrl         string `json:"events_url"`
-		ReceivedEventsUrl string `json:"received_events_url"`
-		Type              string `json:"type"`
-		SiteAdmin         bool   `json:"site_admin"`
-	} `json:"author"`
-	Prerelease  bool      `json:"prerelease"`
-	CreatedAt   time.Time `json:"created_at"`
-	PublishedAt time.Time `json:"published_at"`
-	Assets      []struct {
-		Url      string      `json:"url"`
-		Id       int         `json:"id"`
-		NodeId   string      `json:"node_id"`
-		Name     string      `json:"name"`
-		Label    interface{} `json:"label"`
-		Uploader struct {
-			Login             string `json:"login"`
-			Id                int    `json:"id"`
-			NodeId            string `json:"node_id"`
-			AvatarUrl         string `json:"avatar_url"`
-			GravatarId        string `json:"gravatar_id"`
-			Url               string `json:"url"`
-			HtmlUrl           string `json:"html_url"`
-			FollowersUrl      string `json:"followers_url"`
-			FollowingUrl      string `json:"following_url"`
-			GistsUrl          string `json:"gists_url"`
-			StarredUrl        string `json:"starred_url"`
-			SubscriptionsUrl  string `json:"subscriptions_url"`
-			OrganizationsUrl  string `json:"organizations_url"`
-			ReposUrl          string `json:"repos_url"`
-			EventsUrl         string `json:"events_url"`
-			ReceivedEventsUrl string `json:"received_events_url"`
-			Type              string `json:"type"`
-			SiteAdmin         bool   `json:"site_admin"`
-		} `json:"uploader"`
-		ContentType        string    `json:"content_type"`
-		State              string    `json:"state"`
-		Size               int       `json:"size"`
-		DownloadCount      int       `json:"download_count"`
-		CreatedAt          time.Time `json:"created_at"`
-		UpdatedAt          time.Time `json:"updated_at"`
-		BrowserDownloadUrl string    `json:"browser_download_url"`
-	} `json:"assets"`
-	TarballUrl string `json:"tarball_url"`
-	ZipballUrl string `json:"zipball_url"`
-	Body       string `json:"body"`
+	Url             string    `json:"url"`
+	AssetsUrl       string    `json:"assets_url"`
+	UploadUrl       string    `json:"upload_url"`
+	HtmlUrl         string    `json:"html_url"`
+	Id              int64     `json:"id"`
+	NodeId          string    `json:"node_id"`
+	TagName         string    `json:"tag_name"`
+	TargetCommitish string    `json:"target_commitish"`
+	Name            string    `json:"name"`
+	Draft           bool      `json:"draft"`
+	Author          User      `json:"author"`
+	Prerelease      bool      `json:"prerelease"`
+	CreatedAt       time.Time `json:"created_at"`
+	PublishedAt     time.Time `json:"published_at"`
+	Assets          []Asset   `json:"assets"`
+	TarballUrl      string    `json:"tarball_url"`
+	ZipballUrl      string    `json:"zipball_url"`
+	Body            string    `json:"body"`
 }
 
 type Releases []Release
 
-func SendRequest(url string, method string, body []byte, token string, mime string, v interface{}) (*http.Response, error) {
-
-	req, err := http.NewRequest(method, url, bytes.NewBuffer(body))
-	if err != nil {
-		fmt.Printf("http.NewRequest failed: %v", err)
-		return nil, err
-	}
+// Releases returns an iterator over the releases of a repository, newest first.
+func (c *Client) Releases(owner string, repo string, opts *ListOptions) *ReleaseIterator {
 
-	if mime == "" {
-		mime = "application/json"
-	}
-	req.Header.Set("Content-Type", mime)
+	desc := "list releases for a repository"
+	url := fmt.Sprintf("%s/repos/%s/%s/releases", c.baseURL, owner, repo)
 
-	if token != "" {
-		req.SetBasicAuth(token, "x-oauth-basic")
-	}
+	it := &ReleaseIterator{pager: c.newPager(desc, url, opts)}
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	err := validate(map[string]string{
+		"user": owner,
+		"repo": repo,
+	})
 	if err != nil {
-		fmt.Printf("http.Client.Do failed: %v", err)
-		return nil, err
-	}
-
-	//noinspection GoUnhandledErrorResult
-	if resp.Body != nil {
-		defer resp.Body.Close()
+		it.pager.err = fmt.Errorf("%s: %w", desc, err)
 	}
 
-	data, err := ioutil.ReadAll(resp.Body)
-
-	//logrus.WithFields(logrus.Fields{
-	//	"StatusCode": resp.StatusCode,
-	//	"Header":     resp.Header,
-	//	"Body":       string(data),
-	//}).Debug("send request")
+	return it
+}
 
-	utils.Debug("StatusCode: %v\n", resp.StatusCode)
-	utils.Debug("Header: %v\n", resp.Header)
-	utils.Debug("Body: %s\n", string(data))
+// ListReleases follows the pagination and returns all releases, up to opts.Limit.
+func (c *Client) ListReleases(owner string, repo string, opts *ListOptions) (Releases, error) {
 
-	if err != nil {
-		fmt.Printf("ioutil.ReadAll failed: %v", err)
-		return resp, err
-	}
+	var releases = Releases{}
 
-	if len(data) > 0 && v != nil {
-		err = json.Unmarshal(data, v)
-		if err != nil {
-			fmt.Printf("json.Unmarshal failed: %v", err)
-			return resp, err
-		}
+	it := c.Releases(owner, repo, opts)
+	for it.Next() {
+		releases = append(releases, *it.Release())
 	}
 
-	return resp, nil
-}
-
-func validate(input map[string]string) error {
-
-	for k, v := range input {
-
-		if v == "" {
-			return fmt.Errorf("%s is required", k)
-		}
+	if err := it.Err(); err != nil {
+		return nil, err
 	}
 
-	return nil
+	return releases, nil
 }
 
-// ListReleases
-func ListReleases(owner string, repo string) (Releases, error) {
+func (c *Client) GetRelease(owner string, repo string, releaseId int64) (*Release, error) {
 
-	desc := "list releases for a repository"
-	github := viper.GetString("github")
-	url := fmt.Sprintf("%s/repos/%s/%s/releases", github, owner, repo)
-	token := viper.GetString("token")
+	desc := "get a single release"
+	url := fmt.Sprintf("%s/repos/%s/%s/releases/%d", c.baseURL, owner, repo, releaseId)
 
 	err := validate(map[string]string{
 		"user": owner,
 		"repo": repo,
 	})
 	if err != nil {
-		return nil, fmt.Errorf("%s: %v", desc, err)
-	}
-
-	logrus.WithFields(logrus.Fields{
-		"url": url,
-	}).Info(desc)
-
-	var releases = Releases{}
-	_, err = SendRequest(url, http.MethodGet, nil, token, "", &releases)
-	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("%s: %w", desc, err)
 	}
 
-	color.Green("%20s    %10s    %5s    %s\n", "created", "id", "draft", "tag")
-	for _, r := range releases {
-		fmt.Printf("%20v    %10d    %5v    %s\n",
-			r.CreatedAt.Format("2006-01-02 15:04:05"), r.Id, r.Draft, r.TagName)
-	}
-
-	return releases, nil
+	return c.getRelease(desc, url)
 }
 
-func ListAssets(owner string, repo string) error {
+// GetLatestRelease returns the most recent non-prerelease, non-draft release.
+func (c *Client) GetLatestRelease(owner string, repo string) (*Release, error) {
 
-	desc := "list assets for a release"
-	github := viper.GetString("github")
-	url := fmt.Sprintf("%s/repos/%s/%s/releases/%s/assets", github, owner, repo, viper.GetString("id"))
-	token := viper.GetString("token")
-	method := http.MethodGet
+	desc := "get the latest release"
+	url := fmt.Sprintf("%s/repos/%s/%s/releases/latest", c.baseURL, owner, repo)
 
 	err := validate(map[string]string{
 		"user": owner,
 		"repo": repo,
 	})
 	if err != nil {
-		return fmt.Errorf("%s: %v", desc, err)
-	}
-
-	logrus.WithFields(logrus.Fields{
-		"url": url,
-	}).Info(desc)
-
-	var result []interface{}
-	_, err = SendRequest(url, method, nil, token, "", &result)
-	if err != nil {
-		return err
+		return nil, fmt.Errorf("%s: %w", desc, err)
 	}
 
-	color.Green("%20s    %10s    %10s    %s\n", "created", "id", "size", "name")
-	for _, v := range result {
-		item, _ := v.(map[string]interface{})
-		fmt.Printf("%20v    %10d    %10d    %v\n",
-			item["created_at"], int64(item["id"].(float64)), int64(item["size"].(float64)), item["name"])
-	}
-
-	return nil
+	return c.getRelease(desc, url)
 }
 
-func GetRelease(owner string, repo string, releaseId string) (*Release, error) {
+func (c *Client) GetReleaseByTag(owner string, repo string, tag string) (*Release, error) {
 
-	desc := "get a single release"
-	github := viper.GetString("github")
-	url := fmt.Sprintf("%s/repos/%s/%s/releases/%s", github, owner, repo, releaseId)
+	desc := "get a release by tag name"
+	url := fmt.Sprintf("%s/repos/%s/%s/releases/tags/%s", c.baseURL, owner, repo, tag)
 
 	err := validate(map[string]string{
-		"user":       owner,
-		"repo":       repo,
-		"release_id": releaseId,
+		"user": owner,
+		"repo": repo,
+		"tag":  tag,
 	})
 	if err != nil {
-		return nil, fmt.Errorf("%s: %v", desc, err)
+		return nil, fmt.Errorf("%s: %w", desc, err)
 	}
 
-	logrus.WithFields(logrus.Fields{
-		"url": url,
-	}).Info(desc)
-
-	var release = Release{}
-	_, err = SendRequest(url, http.MethodGet, nil, "", "", &release)
-	if err != nil {
-		return nil, err
-	}
-
-	result, err := json.MarshalIndent(release, "", "\t")
-	fmt.Printf("%s:\n%s\n", desc, string(result))
-
-	return &release, nil
+	return c.getRelease(desc, url)
 }
 
-func GetReleaseByTag(owner string, repo string, tag string) (*Release, error) {
+// FindRelease returns the release of the tag, or nil when there is none. Unlike GetReleaseByTag
+// it also finds draft releases, which have no tag yet and are looked up among the recent releases.
+func (c *Client) FindRelease(owner string, repo string, tag string) (*Release, error) {
 
-	desc := "get a single release"
-	github := viper.GetString("github")
-	url := fmt.Sprintf("%s/repos/%s/%s/releases/tags/%s", github, owner, repo, tag)
+	release, err := c.GetReleaseByTag(owner, repo, tag)
+	if err == nil {
+		return release, nil
+	}
+	if !IsNotFound(err) {
+		return nil, err
+	}
 
-	err := validate(map[string]string{
-		"user": owner,
-		"repo": repo,
-		"tag":  tag,
-	})
+	release, err = c.findRecentRelease(owner, repo, tag)
 	if err != nil {
-		return nil, fmt.Errorf("%s: %v", desc, err)
+		return nil, fmt.Errorf("find a release by tag name: %w", err)
 	}
 
-	logrus.WithFields(logrus.Fields{
-		"url": url,
-	}).Info(desc)
+	return release, nil
+}
+
+func (c *Client) getRelease(desc string, url string) (*Release, error) {
 
 	var release = Release{}
-	_, err = SendRequest(url, http.MethodGet, nil, "", "", &release)
+	_, err := c.SendRequest(url, http.MethodGet, nil, "", &release)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("%s: %w", desc, err)
 	}
 
-	result, err := json.MarshalIndent(release, "", "\t")
-	fmt.Printf("%s:\n%s\n", desc, string(result))
-
 	return &release, nil
 }
 
@@ -300,205 +177,158 @@ type RequestCreateRelease struct {
 	Prerelease      bool   `json:"prerelease"`       // true to identify the release as a prerelease. false to identify the release as a full release. Default: false
 }
 
-func CreateRelease(owner string, repo string) error {
+func (c *Client) CreateRelease(owner string, repo string, request *RequestCreateRelease) (*Release, error) {
 
 	desc := "create a release"
-	github := viper.GetString("github")
-	url := fmt.Sprintf("%s/repos/%s/%s/releases", github, owner, repo)
-	token := viper.GetString("token")
-
-	request := RequestCreateRelease{}
-	request.TagName = viper.GetString("tag_name")
-	request.TargetCommitish = viper.GetString("target_commitish")
-	request.Name = viper.GetString("name")
-	request.Body = viper.GetString("body")
-	request.Draft = viper.GetBool("draft")
-	request.Prerelease = viper.GetBool("prerelease")
-
-	requestByte, _ := json.Marshal(request)
-
-	method := http.MethodPost
-	if viper.GetBool("edit") {
-		method = http.MethodPatch
-		url += fmt.Sprintf("/%s", viper.GetString("id"))
-	}
+	url := fmt.Sprintf("%s/repos/%s/%s/releases", c.baseURL, owner, repo)
 
 	err := validate(map[string]string{
 		"user":  owner,
 		"repo":  repo,
-		"token": token,
+		"token": c.token,
 	})
 	if err != nil {
-		return fmt.Errorf("%s: %v", desc, err)
+		return nil, fmt.Errorf("%s: %w", desc, err)
 	}
 
-	utils.Info("%s, url: %s", desc, url)
+	// Creating a release is not idempotent: a request that failed in transit may still have
+	// created it, so look for the tag before sending the request again.
+	var existing *Release
+	beforeReplay := func() (bool, error) {
+		release, err := c.findRecentRelease(owner, repo, request.TagName)
+		if err != nil {
+			return false, err
+		}
+		existing = release
+		return release == nil, nil
+	}
 
-	var result map[string]interface{}
-	resp, err := SendRequest(url, method, requestByte, token, "", &result)
-	if err != nil {
-		return err
+	release, err := c.writeRelease(desc, url, http.MethodPost, request, beforeReplay)
+	if existing != nil {
+		c.logger.Infof("%s: release %d for tag %s already exists", desc, existing.Id, existing.TagName)
+		return existing, nil
 	}
 
-	if resp.StatusCode == http.StatusCreated {
+	return release, err
+}
 
-		utils.Infof(utils.Fields{
-			"id":       int64(result["id"].(float64)),
-			"tag_name": result["tag_name"],
-			"url":      result["url"],
-		}, "%s success", desc)
+// findRecentRelease looks for the release of the tag among the most recent releases, drafts included.
+func (c *Client) findRecentRelease(owner string, repo string, tag string) (*Release, error) {
 
-		utils.Essential("%d", int64(result["id"].(float64)))
-		return nil
+	it := c.Releases(owner, repo, &ListOptions{PerPage: 100, Limit: 100})
+	for it.Next() {
+		if it.Release().TagName == tag {
+			return it.Release(), nil
+		}
 	}
 
-	printErrors(desc, result)
-
-	return fmt.Errorf("failed")
+	return nil, it.Err()
 }
 
-func DeleteRelease(owner string, repo string) error {
+func (c *Client) EditRelease(owner string, repo string, releaseId int64, request *RequestCreateRelease) (*Release, error) {
 
-	desc := "delete a release"
-	github := viper.GetString("github")
-	id := viper.GetString("id")
-	url := fmt.Sprintf("%s/repos/%s/%s/releases/%s", github, owner, repo, id)
-	token := viper.GetString("token")
-
-	method := http.MethodDelete
+	desc := "edit a release"
+	url := fmt.Sprintf("%s/repos/%s/%s/releases/%d", c.baseURL, owner, repo, releaseId)
 
 	err := validate(map[string]string{
-		"user":       owner,
-		"repo":       repo,
-		"release_id": id,
-		"token":      token,
+		"user":  owner,
+		"repo":  repo,
+		"token": c.token,
 	})
 	if err != nil {
-		return fmt.Errorf("%s: %v", desc, err)
+		return nil, fmt.Errorf("%s: %w", desc, err)
 	}
 
-	logrus.WithFields(logrus.Fields{
-		"url": url,
-	}).Infof(desc)
+	return c.writeRelease(desc, url, http.MethodPatch, request, nil)
+}
+
+// UpsertRelease changes the fields of edit of the release of request.TagName when there is one, draft or not,
+// and creates the release of request otherwise. It reports whether the release was created.
+func (c *Client) UpsertRelease(owner string, repo string, request *RequestCreateRelease, edit *RequestEditRelease) (*Release, bool, error) {
 
-	var result map[string]interface{}
-	resp, err := SendRequest(url, method, nil, token, "", &result)
+	existing, err := c.FindRelease(owner, repo, request.TagName)
 	if err != nil {
-		return err
+		return nil, false, err
 	}
 
-	//content, err := json.MarshalIndent(result, "", "\t")
-	content, err := json.Marshal(result)
-	logrus.WithFields(logrus.Fields{
-		"StatusCode": resp.StatusCode,
-		"Header":     resp.Header,
-		"Body":       string(content),
-	}).Debug(desc)
-
-	if resp.StatusCode == http.StatusNoContent {
-
-		logrus.WithFields(logrus.Fields{
-			"id": id,
-		}).Infof("%s success", desc)
-
-		return nil
+	if existing == nil {
+		release, err := c.CreateRelease(owner, repo, request)
+		return release, err == nil, err
 	}
 
-	if resp.StatusCode == http.StatusNotFound {
-
-		logrus.WithFields(logrus.Fields{
-			"documentation_url": result["documentation_url"],
-			"message":           result["message"],
-		}).Errorf("%s failed", desc)
-	}
+	release, err := c.UpdateRelease(owner, repo, existing.Id, edit)
+	return release, false, err
+}
 
-	return nil
+// RequestEditRelease changes the fields of a release that are not nil, and keeps the others.
+type RequestEditRelease struct {
+	TagName         *string `json:"tag_name,omitempty"`
+	TargetCommitish *string `json:"target_commitish,omitempty"`
+	Name            *string `json:"name,omitempty"`
+	Body            *string `json:"body,omitempty"`
+	Draft           *bool   `json:"draft,omitempty"`
+	Prerelease      *bool   `json:"prerelease,omitempty"`
 }
 
-func UploadAsset(owner string, repo string, filename string, label string) error {
+// String returns a pointer to the value, for the optional fields of RequestEditRelease.
+func String(v string) *string { return &v }
 
-	desc := "upload a release asset"
-	//github := viper.GetString("github")
-	github := "https://uploads.github.com"
-	id := viper.GetString("id")
-	url := fmt.Sprintf("%s/repos/%s/%s/releases/%s/assets?name=%s", github, owner, repo, id, filename)
-	token := viper.GetString("token")
-	method := http.MethodPost
+// Bool returns a pointer to the value, for the optional fields of RequestEditRelease.
+func Bool(v bool) *bool { return &v }
 
-	if label != "" {
-		url += fmt.Sprintf(",label=%s", label)
-	}
+// UpdateRelease changes only the fields set in the request, unlike EditRelease which sends all of them.
+func (c *Client) UpdateRelease(owner string, repo string, releaseId int64, request *RequestEditRelease) (*Release, error) {
+
+	desc := "update a release"
+	url := fmt.Sprintf("%s/repos/%s/%s/releases/%d", c.baseURL, owner, repo, releaseId)
 
 	err := validate(map[string]string{
-		"user":     owner,
-		"repo":     repo,
-		"token":    token,
-		"id":       id,
-		"filename": filename,
+		"user":  owner,
+		"repo":  repo,
+		"token": c.token,
 	})
 	if err != nil {
-		return fmt.Errorf("%s: %v", desc, err)
+		return nil, fmt.Errorf("%s: %w", desc, err)
 	}
 
-	logrus.WithFields(logrus.Fields{
-		"url": url,
-	}).Info(desc)
+	return c.writeRelease(desc, url, http.MethodPatch, request, nil)
+}
+
+func (c *Client) writeRelease(desc string, url string, method string, body interface{},
+	beforeReplay func() (bool, error)) (*Release, error) {
 
-	buf, err := ioutil.ReadFile(filename)
+	requestByte, err := json.Marshal(body)
 	if err != nil {
-		return fmt.Errorf("%s, file read failed: %v", desc, err)
+		return nil, fmt.Errorf("%s: %w", desc, err)
 	}
 
-	mime, _ := mimetype.Detect(buf)
-
-	var result map[string]interface{}
-	resp, err := SendRequest(url, method, buf, token, mime, &result)
+	var release = Release{}
+	_, err = c.do(&rawRequest{url: url, method: method, body: requestByte, beforeReplay: beforeReplay}, &release)
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("%s: %w", desc, err)
 	}
 
-	if resp.StatusCode == http.StatusCreated {
-
-		logrus.WithFields(logrus.Fields{
-			"id":   int64(result["id"].(float64)),
-			"name": result["name"],
-			"url":  result["url"],
-		}).Infof("%s success", desc)
+	return &release, nil
+}
 
-		return nil
-	}
+func (c *Client) DeleteRelease(owner string, repo string, releaseId int64) error {
 
-	if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusBadRequest {
+	desc := "delete a release"
+	url := fmt.Sprintf("%s/repos/%s/%s/releases/%d", c.baseURL, owner, repo, releaseId)
 
-		logrus.WithFields(logrus.Fields{
-			"documentation_url": result["documentation_url"],
-			"message":           result["message"],
-		}).Errorf("%s failed", desc)
+	err := validate(map[string]string{
+		"user":  owner,
+		"repo":  repo,
+		"token": c.token,
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", desc, err)
 	}
 
-	printErrors(desc, result)
+	_, err = c.SendRequest(url, http.MethodDelete, nil, "", nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", desc, err)
+	}
 
 	return nil
 }
-
-func printErrors(desc string, result map[string]interface{}) {
-
-	if val, ok := result["errors"]; ok {
-
-		if errors, ok := val.([]interface{}); ok {
-
-			utils.Error("%s failed with %d errors:", desc, len(errors))
-
-			for i, v := range errors {
-
-				if item, ok := v.(map[string]interface{}); ok {
-					if _, ok := item["message"]; ok {
-						utils.Error("\t%v: %v", color.GreenString("%v", i), item["message"])
-					} else {
-						utils.Error("\t%v: %v %v", color.GreenString("%v", i), item["code"], item["field"])
-					}
-				}
-			}
-		}
-	}
-}
@@ -0,0 +1,139 @@
+package github
+
+import (
+	"errors"
+	"io"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Float64 returns a pointer to the value, for RetryPolicy.Jitter.
+func Float64(v float64) *float64 { return &v }
+
+// RetryPolicy controls how SendRequest retries transient failures: network errors and
+// the configured response status codes. Zero values, and a nil Jitter, fall back to the defaults below.
+type RetryPolicy struct {
+	MaxAttempts int           // Total attempts including the first one, 1 disables retries. Default: 3
+	BaseDelay   time.Duration // Delay before the first retry, doubled on each further attempt. Default: 1s
+	MaxDelay    time.Duration // Upper bound of a single delay. Default: 30s
+	Jitter      *float64      // Fraction of the delay randomly added or removed, between 0 and 1, 0 disables it. Default: 0.2
+	StatusCodes []int         // Response status codes that are retried. Default: 500, 502, 503, 504
+	Methods     []string      // Methods that are safe to replay as is. Default: GET, HEAD, PUT, PATCH, DELETE
+}
+
+// DefaultRetryPolicy is used for every zero field of Options.Retry.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	BaseDelay:   time.Second,
+	MaxDelay:    30 * time.Second,
+	Jitter:      Float64(0.2),
+	StatusCodes: []int{
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout,
+	},
+	Methods: []string{
+		http.MethodGet,
+		http.MethodHead,
+		http.MethodPut,
+		http.MethodPatch,
+		http.MethodDelete,
+	},
+}
+
+func (p RetryPolicy) withDefaults() RetryPolicy {
+
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = DefaultRetryPolicy.MaxAttempts
+	}
+	if p.BaseDelay <= 0 {
+		p.BaseDelay = DefaultRetryPolicy.BaseDelay
+	}
+	if p.MaxDelay <= 0 {
+		p.MaxDelay = DefaultRetryPolicy.MaxDelay
+	}
+	if p.Jitter == nil {
+		p.Jitter = DefaultRetryPolicy.Jitter
+	}
+	if jitter := math.Min(math.Max(*p.Jitter, 0), 1); jitter != *p.Jitter {
+		p.Jitter = Float64(jitter)
+	}
+	if len(p.StatusCodes) == 0 {
+		p.StatusCodes = DefaultRetryPolicy.StatusCodes
+	}
+	if len(p.Methods) == 0 {
+		p.Methods = DefaultRetryPolicy.Methods
+	}
+
+	return p
+}
+
+// transient reports whether a failed attempt is worth retrying.
+func (p RetryPolicy) transient(resp *http.Response, err error) bool {
+
+	if err != nil {
+		// Only network failures are retried: a bad URL, a rejected certificate or a local file that cannot be
+		// read is not going to get better. Every error of http.Client is a *url.Error, which is a net.Error
+		// itself, so the cause is inspected.
+		var urlError *url.Error
+		if errors.As(err, &urlError) {
+			err = urlError.Err
+		}
+		var netError net.Error
+		return errors.As(err, &netError) || errors.Is(err, io.ErrUnexpectedEOF)
+	}
+
+	for _, code := range p.StatusCodes {
+		if resp.StatusCode == code {
+			return true
+		}
+	}
+
+	return false
+}
+
+// transientError is transient for errors already turned into an *APIError.
+func (p RetryPolicy) transientError(err error) bool {
+
+	if e, ok := asAPIError(err); ok {
+		return p.transient(&http.Response{StatusCode: e.StatusCode}, nil)
+	}
+
+	var rate *RateLimitError
+	if errors.As(err, &rate) {
+		return false
+	}
+
+	return p.transient(nil, err)
+}
+
+// idempotent reports whether requests with the method may be replayed without further checks.
+func (p RetryPolicy) idempotent(method string) bool {
+
+	for _, m := range p.Methods {
+		if strings.EqualFold(m, method) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// delay returns the backoff before the given retry, counting from 1.
+func (p RetryPolicy) delay(retry int) time.Duration {
+
+	d := float64(p.BaseDelay) * math.Pow(2, float64(retry-1))
+	if d > float64(p.MaxDelay) {
+		d = float64(p.MaxDelay)
+	}
+
+	d += d * *p.Jitter * (2*rand.Float64() - 1)
+
+	return time.Duration(d)
+}
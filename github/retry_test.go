@@ -0,0 +1,149 @@
+package github
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestTransient(t *testing.T) {
+
+	p := DefaultRetryPolicy
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"connection refused", &url.Error{Op: "Get", URL: "https://api.github.com",
+			Err: &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}}, true},
+		{"wrapped network error", fmt.Errorf("http.Client.Do failed: %w", &url.Error{Op: "Get", URL: "https://api.github.com",
+			Err: &net.OpError{Op: "read", Net: "tcp", Err: errors.New("connection reset by peer")}}), true},
+		{"unexpected EOF", fmt.Errorf("ioutil.ReadAll failed: %w", io.ErrUnexpectedEOF), true},
+		{"bad certificate", &url.Error{Op: "Get", URL: "https://api.github.com", Err: x509.UnknownAuthorityError{}}, false},
+		{"bad URL", &url.Error{Op: "Get", URL: "ftp://api.github.com", Err: errors.New("unsupported protocol scheme")}, false},
+		{"missing file", &os.PathError{Op: "open", Path: "a.zip", Err: os.ErrNotExist}, false},
+	}
+
+	for _, test := range tests {
+		if got := p.transient(nil, test.err); got != test.want {
+			t.Errorf("%s: transient(%v) = %v, want %v", test.name, test.err, got, test.want)
+		}
+	}
+
+	for code, want := range map[int]bool{500: true, 502: true, 503: true, 504: true, 404: false, 422: false} {
+		if got := p.transient(&http.Response{StatusCode: code}, nil); got != want {
+			t.Errorf("transient(status %d) = %v, want %v", code, got, want)
+		}
+	}
+}
+
+func TestRetryPolicyWithDefaults(t *testing.T) {
+
+	p := RetryPolicy{Jitter: Float64(0)}.withDefaults()
+	if p.MaxAttempts != 3 || p.BaseDelay != time.Second || *p.Jitter != 0 {
+		t.Errorf("withDefaults = %d attempts, delay %v, jitter %v, want 3, 1s, 0", p.MaxAttempts, p.BaseDelay, *p.Jitter)
+	}
+
+	if p = (RetryPolicy{Jitter: Float64(2)}).withDefaults(); *p.Jitter != 1 {
+		t.Errorf("withDefaults jitter = %v, want 1", *p.Jitter)
+	}
+
+	p = RetryPolicy{BaseDelay: time.Second, MaxDelay: 3 * time.Second, Jitter: Float64(0)}.withDefaults()
+	for retry, want := range map[int]time.Duration{1: time.Second, 2: 2 * time.Second, 3: 3 * time.Second} {
+		if delay := p.delay(retry); delay != want {
+			t.Errorf("delay(%d) = %v, want %v", retry, delay, want)
+		}
+	}
+}
+
+// failingServer answers the first failures requests with 503 and the others with 200.
+func failingServer(failures int32, requests *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(requests, 1) <= failures {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"id":1}`))
+	}))
+}
+
+func retryClient(server *httptest.Server, attempts int) *Client {
+	return NewClient(Options{
+		BaseURL: server.URL,
+		Retry:   RetryPolicy{MaxAttempts: attempts, BaseDelay: time.Millisecond, Jitter: Float64(0)},
+	})
+}
+
+func TestDoRetry(t *testing.T) {
+
+	tests := []struct {
+		name         string
+		method       string
+		attempts     int
+		failures     int32
+		replay       func() (bool, error)
+		wantRequests int32
+		wantStatus   int
+	}{
+		{"GET succeeds after retries", http.MethodGet, 3, 2, nil, 3, http.StatusOK},
+		{"GET gives up", http.MethodGet, 3, 5, nil, 3, http.StatusServiceUnavailable},
+		{"single attempt", http.MethodGet, 1, 5, nil, 1, http.StatusServiceUnavailable},
+		{"POST is not replayed", http.MethodPost, 3, 1, nil, 1, http.StatusServiceUnavailable},
+		{"POST replay refused", http.MethodPost, 3, 1, func() (bool, error) { return false, nil }, 1, http.StatusServiceUnavailable},
+		{"POST replay check fails", http.MethodPost, 3, 1, func() (bool, error) { return true, errors.New("list failed") }, 1, http.StatusServiceUnavailable},
+		{"POST replayed", http.MethodPost, 3, 1, func() (bool, error) { return true, nil }, 2, http.StatusOK},
+	}
+
+	for _, test := range tests {
+
+		var requests int32
+		server := failingServer(test.failures, &requests)
+		c := retryClient(server, test.attempts)
+
+		var v struct{ Id int64 }
+		resp, err := c.do(&rawRequest{url: server.URL, method: test.method, beforeReplay: test.replay}, &v)
+		server.Close()
+
+		if requests != test.wantRequests {
+			t.Errorf("%s: %d requests, want %d", test.name, requests, test.wantRequests)
+		}
+		if resp == nil || resp.StatusCode != test.wantStatus {
+			t.Errorf("%s: response %v, %v, want status %d", test.name, resp, err, test.wantStatus)
+			continue
+		}
+
+		var apiError *APIError
+		if test.wantStatus == http.StatusOK && (err != nil || v.Id != 1) {
+			t.Errorf("%s: do = %v, id %d, want success", test.name, err, v.Id)
+		}
+		if test.wantStatus != http.StatusOK && !errors.As(err, &apiError) {
+			t.Errorf("%s: do = %v, want an *APIError", test.name, err)
+		}
+	}
+}
+
+func TestDoNotRetriedError(t *testing.T) {
+
+	var requests int32
+	server := failingServer(0, &requests)
+	defer server.Close()
+
+	c := retryClient(server, 3)
+	_, err := c.do(&rawRequest{url: server.URL, method: http.MethodPut, open: func() (io.ReadCloser, int64, error) {
+		return nil, 0, &os.PathError{Op: "open", Path: "missing.zip", Err: os.ErrNotExist}
+	}}, nil)
+
+	if !errors.Is(err, os.ErrNotExist) || requests != 0 {
+		t.Errorf("do = %v after %d requests, want the path error without requests", err, requests)
+	}
+}
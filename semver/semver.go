@@ -0,0 +1,189 @@
+// Package semver parses and formats versions of Semantic Versioning 2.0.0, https://semver.org.
+package semver
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Version is a version MAJOR.MINOR.PATCH with optional pre-release and build identifiers,
+// e.g. 1.2.3-rc.1+build.5.
+type Version struct {
+	Major      uint64
+	Minor      uint64
+	Patch      uint64
+	Prerelease []string
+	Build      []string
+}
+
+// Part is the part of a version that Bump increments.
+type Part string
+
+const (
+	Major      Part = "major"
+	Minor      Part = "minor"
+	Patch      Part = "patch"
+	Prerelease Part = "prerelease"
+)
+
+// Parts lists the parts Bump increments.
+var Parts = []Part{Major, Minor, Patch, Prerelease}
+
+// Parse parses a version without a leading "v".
+func Parse(s string) (Version, error) {
+
+	var v Version
+	rest := s
+
+	if i := strings.IndexByte(rest, '+'); i >= 0 {
+		build, err := identifiers(rest[i+1:], false)
+		if err != nil {
+			return Version{}, fmt.Errorf("invalid version %q: build %w", s, err)
+		}
+		v.Build = build
+		rest = rest[:i]
+	}
+
+	if i := strings.IndexByte(rest, '-'); i >= 0 {
+		pre, err := identifiers(rest[i+1:], true)
+		if err != nil {
+			return Version{}, fmt.Errorf("invalid version %q: pre-release %w", s, err)
+		}
+		v.Prerelease = pre
+		rest = rest[:i]
+	}
+
+	parts := strings.Split(rest, ".")
+	if len(parts) != 3 {
+		return Version{}, fmt.Errorf("invalid version %q: want MAJOR.MINOR.PATCH", s)
+	}
+
+	for i, field := range []*uint64{&v.Major, &v.Minor, &v.Patch} {
+		n, err := number(parts[i])
+		if err != nil {
+			return Version{}, fmt.Errorf("invalid version %q: %w", s, err)
+		}
+		*field = n
+	}
+
+	return v, nil
+}
+
+// String formats the version, e.g. 1.2.3-rc.1+build.5.
+func (v Version) String() string {
+
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if len(v.Prerelease) > 0 {
+		s += "-" + strings.Join(v.Prerelease, ".")
+	}
+	if len(v.Build) > 0 {
+		s += "+" + strings.Join(v.Build, ".")
+	}
+
+	return s
+}
+
+// Bump returns the next version, without build metadata. A pre-release is released by the part it leads
+// to, e.g. 1.3.0-rc.1 bumps the minor to 1.3.0 and the patch to 1.3.0, and the major to 2.0.0.
+//
+// Prerelease increments the last number of the pre-release, e.g. 1.3.0-rc.1 to 1.3.0-rc.2, or starts a
+// pre-release of the next patch, e.g. 1.2.3 to 1.2.4-rc.1. A preid, e.g. beta, different from the first
+// identifier of the pre-release starts a new one, e.g. 1.3.0-alpha.2 to 1.3.0-beta.1; the default is rc.
+func (v Version) Bump(part Part, preid string) (Version, error) {
+
+	next := Version{Major: v.Major, Minor: v.Minor, Patch: v.Patch}
+	pre := len(v.Prerelease) > 0
+
+	switch part {
+	case Major:
+		if !pre || v.Minor != 0 || v.Patch != 0 {
+			next = Version{Major: v.Major + 1}
+		}
+
+	case Minor:
+		if !pre || v.Patch != 0 {
+			next = Version{Major: v.Major, Minor: v.Minor + 1}
+		}
+
+	case Patch:
+		if !pre {
+			next.Patch++
+		}
+
+	case Prerelease:
+		if _, err := identifiers(preid, true); preid != "" && err != nil {
+			return Version{}, fmt.Errorf("invalid pre-release %q: %w", preid, err)
+		}
+
+		if pre && (preid == "" || preid == v.Prerelease[0]) {
+			next.Prerelease = increment(v.Prerelease)
+			break
+		}
+
+		if !pre {
+			next.Patch++
+		}
+		if preid == "" {
+			preid = "rc"
+		}
+		next.Prerelease = []string{preid, "1"}
+
+	default:
+		return Version{}, fmt.Errorf("unknown version part %q", part)
+	}
+
+	return next, nil
+}
+
+// increment increments the last numeric identifier, or appends 1 when there is none.
+func increment(ids []string) []string {
+
+	next := append([]string(nil), ids...)
+	for i := len(next) - 1; i >= 0; i-- {
+		if n, err := number(next[i]); err == nil {
+			next[i] = strconv.FormatUint(n+1, 10)
+			return next
+		}
+	}
+
+	return append(next, "1")
+}
+
+// number parses a numeric identifier, which has no leading zeros.
+func number(s string) (uint64, error) {
+
+	if s == "" || strings.Trim(s, "0123456789") != "" {
+		return 0, fmt.Errorf("%q is not a number", s)
+	}
+	if len(s) > 1 && s[0] == '0' {
+		return 0, fmt.Errorf("%q has a leading zero", s)
+	}
+
+	return strconv.ParseUint(s, 10, 64)
+}
+
+// identifiers splits dot separated identifiers of [0-9A-Za-z-]. Numeric pre-release identifiers have no
+// leading zeros.
+func identifiers(s string, prerelease bool) ([]string, error) {
+
+	ids := strings.Split(s, ".")
+	for _, id := range ids {
+
+		if id == "" {
+			return nil, fmt.Errorf("has an empty identifier")
+		}
+		for _, r := range id {
+			if !(r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r == '-') {
+				return nil, fmt.Errorf("identifier %q has an invalid character %q", id, r)
+			}
+		}
+		if prerelease && strings.Trim(id, "0123456789") == "" {
+			if _, err := number(id); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return ids, nil
+}
@@ -0,0 +1,131 @@
+package semver
+
+import (
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+
+	tests := []struct {
+		in   string
+		want Version
+		err  bool
+	}{
+		{in: "1.2.3", want: Version{Major: 1, Minor: 2, Patch: 3}},
+		{in: "0.0.0", want: Version{}},
+		{in: "1.2.3-rc.1", want: Version{Major: 1, Minor: 2, Patch: 3, Prerelease: []string{"rc", "1"}}},
+		{in: "1.2.3+build.5", want: Version{Major: 1, Minor: 2, Patch: 3, Build: []string{"build", "5"}}},
+		{in: "1.2.3-alpha-1.0a+001", want: Version{Major: 1, Minor: 2, Patch: 3,
+			Prerelease: []string{"alpha-1", "0a"}, Build: []string{"001"}}},
+		{in: "10.20.30-x.7.z.92", want: Version{Major: 10, Minor: 20, Patch: 30,
+			Prerelease: []string{"x", "7", "z", "92"}}},
+		{in: "", err: true},
+		{in: "1.2", err: true},
+		{in: "1.2.3.4", err: true},
+		{in: "v1.2.3", err: true},
+		{in: "01.2.3", err: true},
+		{in: "1.2.x", err: true},
+		{in: "1.2.3-", err: true},
+		{in: "1.2.3-rc..1", err: true},
+		{in: "1.2.3-rc.01", err: true},
+		{in: "1.2.3-rc_1", err: true},
+		{in: "1.2.3+", err: true},
+		{in: "1.2.3+b@d", err: true},
+	}
+
+	for _, test := range tests {
+
+		got, err := Parse(test.in)
+		if test.err {
+			if err == nil {
+				t.Errorf("Parse(%q) = %v, want an error", test.in, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Parse(%q) failed: %v", test.in, err)
+			continue
+		}
+		if got.String() != test.want.String() {
+			t.Errorf("Parse(%q) = %v, want %v", test.in, got, test.want)
+		}
+	}
+}
+
+func TestString(t *testing.T) {
+
+	tests := []struct {
+		in   Version
+		want string
+	}{
+		{in: Version{}, want: "0.0.0"},
+		{in: Version{Major: 1, Minor: 2, Patch: 3}, want: "1.2.3"},
+		{in: Version{Major: 1, Minor: 2, Patch: 3, Prerelease: []string{"rc", "1"}}, want: "1.2.3-rc.1"},
+		{in: Version{Major: 1, Minor: 2, Patch: 3, Build: []string{"abc1234"}}, want: "1.2.3+abc1234"},
+		{in: Version{Major: 1, Prerelease: []string{"beta"}, Build: []string{"build", "5"}}, want: "1.0.0-beta+build.5"},
+	}
+
+	for _, test := range tests {
+		if got := test.in.String(); got != test.want {
+			t.Errorf("%#v.String() = %q, want %q", test.in, got, test.want)
+		}
+	}
+}
+
+func TestBump(t *testing.T) {
+
+	tests := []struct {
+		in    string
+		part  Part
+		preid string
+		want  string
+		err   bool
+	}{
+		{in: "1.2.3", part: Major, want: "2.0.0"},
+		{in: "1.2.3", part: Minor, want: "1.3.0"},
+		{in: "1.2.3", part: Patch, want: "1.2.4"},
+		{in: "1.2.3+build.5", part: Patch, want: "1.2.4"},
+
+		// A pre-release is released by the part it leads to.
+		{in: "2.0.0-rc.1", part: Major, want: "2.0.0"},
+		{in: "1.3.0-rc.1", part: Major, want: "2.0.0"},
+		{in: "1.3.0-rc.1", part: Minor, want: "1.3.0"},
+		{in: "1.2.4-rc.1", part: Minor, want: "1.3.0"},
+		{in: "1.3.0-rc.1", part: Patch, want: "1.3.0"},
+
+		{in: "1.2.3", part: Prerelease, want: "1.2.4-rc.1"},
+		{in: "1.2.3", part: Prerelease, preid: "beta", want: "1.2.4-beta.1"},
+		{in: "1.3.0-rc.1", part: Prerelease, want: "1.3.0-rc.2"},
+		{in: "1.3.0-rc.1", part: Prerelease, preid: "rc", want: "1.3.0-rc.2"},
+		{in: "1.3.0-alpha.2", part: Prerelease, preid: "beta", want: "1.3.0-beta.1"},
+		{in: "1.3.0-rc.1.beta", part: Prerelease, want: "1.3.0-rc.2.beta"},
+		{in: "1.3.0-rc", part: Prerelease, want: "1.3.0-rc.1"},
+		{in: "1.3.0-rc.9+build.5", part: Prerelease, want: "1.3.0-rc.10"},
+
+		{in: "1.2.3", part: Prerelease, preid: "be_ta", err: true},
+		{in: "1.2.3", part: Part("build"), err: true},
+	}
+
+	for _, test := range tests {
+
+		v, err := Parse(test.in)
+		if err != nil {
+			t.Fatalf("Parse(%q) failed: %v", test.in, err)
+		}
+
+		got, err := v.Bump(test.part, test.preid)
+		if test.err {
+			if err == nil {
+				t.Errorf("%s.Bump(%s, %q) = %v, want an error", test.in, test.part, test.preid, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s.Bump(%s, %q) failed: %v", test.in, test.part, test.preid, err)
+			continue
+		}
+		if got.String() != test.want {
+			t.Errorf("%s.Bump(%s, %q) = %v, want %s", test.in, test.part, test.preid, got, test.want)
+		}
+	}
+}
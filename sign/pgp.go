@@ -0,0 +1,82 @@
+package sign
+
+import (
+	"bytes"
+	"fmt"
+	"golang.org/x/crypto/openpgp"
+	"golang.org/x/crypto/openpgp/armor"
+	"io"
+	"io/ioutil"
+)
+
+type pgpSigner struct {
+	entity *openpgp.Entity
+}
+
+// NewPGPSigner loads the first private key of an armored or binary OpenPGP key ring,
+// decrypting it with the passphrase when it is protected.
+func NewPGPSigner(keyPath string, passphrase []byte) (Signer, error) {
+
+	entities, err := readKeyRing(keyPath)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entity := range entities {
+		if entity.PrivateKey == nil {
+			continue
+		}
+
+		if entity.PrivateKey.Encrypted {
+			if len(passphrase) == 0 {
+				return nil, fmt.Errorf("%s: the key is protected by a passphrase", keyPath)
+			}
+			if err = entity.PrivateKey.Decrypt(passphrase); err != nil {
+				return nil, fmt.Errorf("%s: %w", keyPath, err)
+			}
+		}
+
+		for _, subkey := range entity.Subkeys {
+			if subkey.PrivateKey != nil && subkey.PrivateKey.Encrypted {
+				if err = subkey.PrivateKey.Decrypt(passphrase); err != nil {
+					return nil, fmt.Errorf("%s: %w", keyPath, err)
+				}
+			}
+		}
+
+		return &pgpSigner{entity: entity}, nil
+	}
+
+	return nil, fmt.Errorf("%s: no private key found", keyPath)
+}
+
+// readKeyRing reads an armored or binary key ring.
+func readKeyRing(path string) (openpgp.EntityList, error) {
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if block, err := armor.Decode(bytes.NewReader(data)); err == nil {
+		data, err = ioutil.ReadAll(block.Body)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+
+	entities, err := openpgp.ReadKeyRing(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return entities, nil
+}
+
+func (s *pgpSigner) Extension() string {
+	return ".asc"
+}
+
+func (s *pgpSigner) Sign(message io.Reader, w io.Writer) error {
+	return openpgp.ArmoredDetachSign(w, s.entity, message, nil)
+}
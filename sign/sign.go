@@ -0,0 +1,48 @@
+// Package sign produces detached signatures of release assets: OpenPGP armored
+// signatures (.asc) and SSH signatures in the format of ssh-keygen -Y sign (.sig).
+package sign
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// Signer produces a detached signature of a message.
+type Signer interface {
+	// Extension is appended to the name of the signed file, e.g. ".asc".
+	Extension() string
+	// Sign writes the signature of message to w.
+	Sign(message io.Reader, w io.Writer) error
+}
+
+// File signs the file at path and writes the signature to dir, named after
+// the file with the extension of the signer. It returns the path of the signature.
+func File(signer Signer, path string, name string, dir string) (string, error) {
+
+	message, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+
+	//noinspection GoUnhandledErrorResult
+	defer message.Close()
+
+	sigPath := fmt.Sprintf("%s%c%s%s", dir, os.PathSeparator, name, signer.Extension())
+
+	signature, err := os.Create(sigPath)
+	if err != nil {
+		return "", err
+	}
+
+	err = signer.Sign(message, signature)
+	if closeErr := signature.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(sigPath)
+		return "", fmt.Errorf("sign %s: %w", path, err)
+	}
+
+	return sigPath, nil
+}
@@ -0,0 +1,127 @@
+package sign
+
+import (
+	stded25519 "crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha512"
+	"encoding/base64"
+	"fmt"
+	"golang.org/x/crypto/ed25519"
+	"golang.org/x/crypto/ssh"
+	"io"
+	"io/ioutil"
+)
+
+// sshSigMagic starts both the signature blob and the signed data of the SSHSIG format,
+// see PROTOCOL.sshsig in the OpenSSH sources.
+const sshSigMagic = "SSHSIG"
+
+// DefaultNamespace is the namespace ssh-keygen -Y sign uses for files.
+const DefaultNamespace = "file"
+
+type sshSigner struct {
+	signer    ssh.Signer
+	namespace string
+}
+
+// NewSSHSigner loads an OpenSSH or PEM private key and signs in the format of
+// ssh-keygen -Y sign -n namespace, verifiable with ssh-keygen -Y verify.
+func NewSSHSigner(keyPath string, passphrase []byte, namespace string) (Signer, error) {
+
+	data, err := ioutil.ReadFile(keyPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var key interface{}
+	if len(passphrase) == 0 {
+		key, err = ssh.ParseRawPrivateKey(data)
+	} else {
+		key, err = ssh.ParseRawPrivateKeyWithPassphrase(data, passphrase)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", keyPath, err)
+	}
+
+	// PKCS8 ed25519 keys are parsed by crypto/x509 into the standard library type, unknown to ssh.
+	if k, ok := key.(stded25519.PrivateKey); ok {
+		key = ed25519.PrivateKey(k)
+	}
+
+	signer, err := ssh.NewSignerFromKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", keyPath, err)
+	}
+
+	if namespace == "" {
+		namespace = DefaultNamespace
+	}
+
+	return &sshSigner{signer: signer, namespace: namespace}, nil
+}
+
+func (s *sshSigner) Extension() string {
+	return ".sig"
+}
+
+func (s *sshSigner) Sign(message io.Reader, w io.Writer) error {
+
+	h := sha512.New()
+	if _, err := io.Copy(h, message); err != nil {
+		return err
+	}
+
+	signedData := append([]byte(sshSigMagic), ssh.Marshal(struct {
+		Namespace     string
+		Reserved      string
+		HashAlgorithm string
+		Hash          []byte
+	}{s.namespace, "", "sha512", h.Sum(nil)})...)
+
+	var signature *ssh.Signature
+	var err error
+	if algorithmSigner, ok := s.signer.(ssh.AlgorithmSigner); ok && s.signer.PublicKey().Type() == ssh.KeyAlgoRSA {
+		// ssh-keygen refuses the SHA-1 ssh-rsa signature algorithm.
+		signature, err = algorithmSigner.SignWithAlgorithm(rand.Reader, signedData, ssh.SigAlgoRSASHA2512)
+	} else {
+		signature, err = s.signer.Sign(rand.Reader, signedData)
+	}
+	if err != nil {
+		return err
+	}
+
+	blob := append([]byte(sshSigMagic), ssh.Marshal(struct {
+		Version       uint32
+		PublicKey     []byte
+		Namespace     string
+		Reserved      string
+		HashAlgorithm string
+		Signature     []byte
+	}{1, s.signer.PublicKey().Marshal(), s.namespace, "", "sha512", ssh.Marshal(signature)})...)
+
+	return armorSSH(w, blob)
+}
+
+// armorSSH writes the blob base64 encoded in lines of 70 characters, as ssh-keygen does.
+func armorSSH(w io.Writer, blob []byte) error {
+
+	encoded := base64.StdEncoding.EncodeToString(blob)
+
+	if _, err := io.WriteString(w, "-----BEGIN SSH SIGNATURE-----\n"); err != nil {
+		return err
+	}
+
+	for len(encoded) > 0 {
+		n := 70
+		if len(encoded) < n {
+			n = len(encoded)
+		}
+		if _, err := io.WriteString(w, encoded[:n]+"\n"); err != nil {
+			return err
+		}
+		encoded = encoded[n:]
+	}
+
+	_, err := io.WriteString(w, "-----END SSH SIGNATURE-----\n")
+	return err
+}
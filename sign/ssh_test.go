@@ -0,0 +1,181 @@
+package sign
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"golang.org/x/crypto/ssh"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeKey writes the private key as a PKCS #8 PEM file to dir.
+func writeKey(t *testing.T, dir string, name string, key interface{}) string {
+
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(dir, name)
+	if err := ioutil.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func TestSSHSignerFormat(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "sign")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	_, ed25519Key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecdsaKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		key       interface{}
+		namespace string
+		format    string
+	}{
+		{"ed25519", ed25519Key, "", ssh.KeyAlgoED25519},
+		{"ecdsa", ecdsaKey, "release", ssh.KeyAlgoECDSA256},
+		{"rsa", rsaKey, "file", ssh.SigAlgoRSASHA2512}, // Not the SHA-1 ssh-rsa, which ssh-keygen refuses.
+	}
+
+	message := []byte("the contents of an asset\n")
+
+	for _, test := range tests {
+
+		signer, err := NewSSHSigner(writeKey(t, dir, test.name, test.key), nil, test.namespace)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if signer.Extension() != ".sig" {
+			t.Errorf("%s: extension %q, want .sig", test.name, signer.Extension())
+		}
+
+		var armored bytes.Buffer
+		if err := signer.Sign(bytes.NewReader(message), &armored); err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+
+		lines := strings.Split(strings.TrimSuffix(armored.String(), "\n"), "\n")
+		if lines[0] != "-----BEGIN SSH SIGNATURE-----" || lines[len(lines)-1] != "-----END SSH SIGNATURE-----" {
+			t.Fatalf("%s: not armored:\n%s", test.name, armored.String())
+		}
+		for _, line := range lines[1 : len(lines)-1] {
+			if len(line) > 70 {
+				t.Errorf("%s: line of %d characters, want at most 70", test.name, len(line))
+			}
+		}
+
+		blob, err := base64.StdEncoding.DecodeString(strings.Join(lines[1:len(lines)-1], ""))
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if !bytes.HasPrefix(blob, []byte(sshSigMagic)) {
+			t.Fatalf("%s: blob does not start with %s", test.name, sshSigMagic)
+		}
+
+		var sig struct {
+			Version       uint32
+			PublicKey     []byte
+			Namespace     string
+			Reserved      string
+			HashAlgorithm string
+			Signature     []byte
+		}
+		if err := ssh.Unmarshal(blob[len(sshSigMagic):], &sig); err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+
+		namespace := test.namespace
+		if namespace == "" {
+			namespace = DefaultNamespace
+		}
+		if sig.Version != 1 || sig.Namespace != namespace || sig.Reserved != "" || sig.HashAlgorithm != "sha512" {
+			t.Errorf("%s: version %d, namespace %q, reserved %q, hash %q, want 1, %q, \"\", sha512",
+				test.name, sig.Version, sig.Namespace, sig.Reserved, sig.HashAlgorithm, namespace)
+		}
+
+		publicKey, err := ssh.ParsePublicKey(sig.PublicKey)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+
+		var signature ssh.Signature
+		if err := ssh.Unmarshal(sig.Signature, &signature); err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if signature.Format != test.format {
+			t.Errorf("%s: signature algorithm %s, want %s", test.name, signature.Format, test.format)
+		}
+
+		h := sha512.Sum512(message)
+		signedData := append([]byte(sshSigMagic), ssh.Marshal(struct {
+			Namespace     string
+			Reserved      string
+			HashAlgorithm string
+			Hash          []byte
+		}{namespace, "", "sha512", h[:]})...)
+		if err := publicKey.Verify(signedData, &signature); err != nil {
+			t.Errorf("%s: signature does not verify: %v", test.name, err)
+		}
+
+		verifyWithSSHKeygen(t, dir, test.name, publicKey, namespace, message, armored.Bytes())
+	}
+}
+
+// verifyWithSSHKeygen checks the signature with ssh-keygen -Y verify of OpenSSH 8.1 or later, when it is
+// installed.
+func verifyWithSSHKeygen(t *testing.T, dir string, name string, key ssh.PublicKey, namespace string,
+	message []byte, signature []byte) {
+
+	if _, err := exec.LookPath("ssh-keygen"); err != nil {
+		return
+	}
+
+	allowedSigners := filepath.Join(dir, name+".allowed_signers")
+	line := "release@example.com " + strings.TrimSpace(string(ssh.MarshalAuthorizedKey(key))) + "\n"
+	if err := ioutil.WriteFile(allowedSigners, []byte(line), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	sigPath := filepath.Join(dir, name+".sig")
+	if err := ioutil.WriteFile(sigPath, signature, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	command := exec.Command("ssh-keygen", "-Y", "verify", "-f", allowedSigners, "-I", "release@example.com",
+		"-n", namespace, "-s", sigPath)
+	command.Stdin = bytes.NewReader(message)
+	if output, err := command.CombinedOutput(); err != nil {
+		t.Errorf("%s: ssh-keygen -Y verify: %v: %s", name, err, output)
+	}
+}
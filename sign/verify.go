@@ -0,0 +1,322 @@
+package sign
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"golang.org/x/crypto/openpgp"
+	"golang.org/x/crypto/ssh"
+	"hash"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrBadSignature is returned when a signature does not match the file or is not made by a trusted key.
+	ErrBadSignature = errors.New("bad signature")
+	// ErrUnsigned is returned when a file has no signature to verify.
+	ErrUnsigned = errors.New("no signature")
+)
+
+// Verifier checks detached signatures of one kind.
+type Verifier interface {
+	// Extension is the extension of the signature files the verifier checks, e.g. ".asc".
+	Extension() string
+	// Verify checks signature against message and returns the identity of the signer.
+	Verify(message io.Reader, signature []byte) (string, error)
+}
+
+type pgpVerifier struct {
+	keyring openpgp.EntityList
+}
+
+// NewPGPVerifier trusts the keys of an armored or binary OpenPGP key ring, e.g. gpg --export.
+func NewPGPVerifier(keyringPath string) (Verifier, error) {
+
+	keyring, err := readKeyRing(keyringPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pgpVerifier{keyring: keyring}, nil
+}
+
+func (v *pgpVerifier) Extension() string {
+	return ".asc"
+}
+
+func (v *pgpVerifier) Verify(message io.Reader, signature []byte) (string, error) {
+
+	var signer *openpgp.Entity
+	var err error
+	if bytes.HasPrefix(bytes.TrimSpace(signature), []byte("-----BEGIN")) {
+		signer, err = openpgp.CheckArmoredDetachedSignature(v.keyring, message, bytes.NewReader(signature))
+	} else {
+		signer, err = openpgp.CheckDetachedSignature(v.keyring, message, bytes.NewReader(signature))
+	}
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrBadSignature, err)
+	}
+
+	for name := range signer.Identities {
+		return name, nil
+	}
+
+	return signer.PrimaryKey.KeyIdString(), nil
+}
+
+// allowedSigner is a line of an allowed signers file, see ALLOWED SIGNERS in ssh-keygen(1).
+type allowedSigner struct {
+	principals  string
+	namespaces  []string
+	validAfter  time.Time // Zero when the key is valid since ever.
+	validBefore time.Time // Zero when the key does not expire.
+	key         ssh.PublicKey
+}
+
+type sshVerifier struct {
+	signers   []allowedSigner
+	namespace string
+}
+
+// NewSSHVerifier trusts the keys of an allowed signers file as used by ssh-keygen -Y verify.
+// Signatures must be made for the namespace, default "file", within the valid-after and valid-before times
+// of the key. Certificate authorities are not supported, their lines trust no key.
+func NewSSHVerifier(allowedSignersPath string, namespace string) (Verifier, error) {
+
+	file, err := os.Open(allowedSignersPath)
+	if err != nil {
+		return nil, err
+	}
+
+	//noinspection GoUnhandledErrorResult
+	defer file.Close()
+
+	v := &sshVerifier{namespace: namespace}
+	if v.namespace == "" {
+		v.namespace = DefaultNamespace
+	}
+
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		fields := strings.SplitN(text, " ", 2)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("%s line %d: expected \"<principals> [options] <key>\"", allowedSignersPath, line)
+		}
+
+		// ParseAuthorizedKey accepts the same options before the key as authorized_keys.
+		key, _, options, _, err := ssh.ParseAuthorizedKey([]byte(fields[1]))
+		if err != nil {
+			return nil, fmt.Errorf("%s line %d: %w", allowedSignersPath, line, err)
+		}
+
+		signer := allowedSigner{principals: fields[0], key: key}
+		trusted := true
+		for _, option := range options {
+			name := strings.ToLower(option)
+			switch {
+			case name == "cert-authority":
+				trusted = false
+			case strings.HasPrefix(name, "namespaces="):
+				signer.namespaces = strings.Split(optionValue(option), ",")
+			case strings.HasPrefix(name, "valid-after="):
+				signer.validAfter, err = parseSignerTime(optionValue(option))
+			case strings.HasPrefix(name, "valid-before="):
+				signer.validBefore, err = parseSignerTime(optionValue(option))
+			}
+			if err != nil {
+				return nil, fmt.Errorf("%s line %d: %w", allowedSignersPath, line, err)
+			}
+		}
+
+		if trusted {
+			v.signers = append(v.signers, signer)
+		}
+	}
+
+	if err = scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
+
+func (v *sshVerifier) Extension() string {
+	return ".sig"
+}
+
+func (v *sshVerifier) Verify(message io.Reader, signature []byte) (string, error) {
+
+	blob, err := unarmorSSH(signature)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrBadSignature, err)
+	}
+
+	var sig struct {
+		Version       uint32
+		PublicKey     []byte
+		Namespace     string
+		Reserved      string
+		HashAlgorithm string
+		Signature     []byte
+	}
+	if !bytes.HasPrefix(blob, []byte(sshSigMagic)) || ssh.Unmarshal(blob[len(sshSigMagic):], &sig) != nil {
+		return "", fmt.Errorf("%w: not an SSH signature", ErrBadSignature)
+	}
+
+	if sig.Version != 1 {
+		return "", fmt.Errorf("%w: unsupported SSH signature version %d", ErrBadSignature, sig.Version)
+	}
+
+	if sig.Namespace != v.namespace {
+		return "", fmt.Errorf("%w: signed for namespace %q, expected %q", ErrBadSignature, sig.Namespace, v.namespace)
+	}
+
+	key, err := ssh.ParsePublicKey(sig.PublicKey)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrBadSignature, err)
+	}
+
+	signer := v.allowed(key, time.Now())
+	if signer == nil {
+		return "", fmt.Errorf("%w: key %s is not an allowed signer", ErrBadSignature, ssh.FingerprintSHA256(key))
+	}
+
+	var h hash.Hash
+	switch sig.HashAlgorithm {
+	case "sha256":
+		h = sha256.New()
+	case "sha512":
+		h = sha512.New()
+	default:
+		return "", fmt.Errorf("%w: unsupported hash algorithm %q", ErrBadSignature, sig.HashAlgorithm)
+	}
+
+	if _, err = io.Copy(h, message); err != nil {
+		return "", err
+	}
+
+	signedData := append([]byte(sshSigMagic), ssh.Marshal(struct {
+		Namespace     string
+		Reserved      string
+		HashAlgorithm string
+		Hash          []byte
+	}{sig.Namespace, sig.Reserved, sig.HashAlgorithm, h.Sum(nil)})...)
+
+	var s ssh.Signature
+	if err = ssh.Unmarshal(sig.Signature, &s); err != nil {
+		return "", fmt.Errorf("%w: %v", ErrBadSignature, err)
+	}
+
+	// ssh-keygen refuses the SHA-1 ssh-rsa signature algorithm, as Sign does.
+	if key.Type() == ssh.KeyAlgoRSA && s.Format != ssh.SigAlgoRSASHA2256 && s.Format != ssh.SigAlgoRSASHA2512 {
+		return "", fmt.Errorf("%w: signature algorithm %s, expected %s or %s", ErrBadSignature, s.Format,
+			ssh.SigAlgoRSASHA2256, ssh.SigAlgoRSASHA2512)
+	}
+
+	if err = key.Verify(signedData, &s); err != nil {
+		return "", fmt.Errorf("%w: %v", ErrBadSignature, err)
+	}
+
+	return signer.principals, nil
+}
+
+// allowed returns the allowed signer with the key for the namespace of the verifier and valid at now, or nil.
+func (v *sshVerifier) allowed(key ssh.PublicKey, now time.Time) *allowedSigner {
+
+	marshaled := key.Marshal()
+	for i := range v.signers {
+
+		signer := &v.signers[i]
+		if !bytes.Equal(signer.key.Marshal(), marshaled) {
+			continue
+		}
+
+		if !signer.validAfter.IsZero() && now.Before(signer.validAfter) ||
+			!signer.validBefore.IsZero() && now.After(signer.validBefore) {
+			continue
+		}
+
+		if signer.namespaces == nil {
+			return signer
+		}
+
+		for _, namespace := range signer.namespaces {
+			if namespace == v.namespace {
+				return signer
+			}
+		}
+	}
+
+	return nil
+}
+
+// optionValue returns the value of a name="value" option, without the quotes.
+func optionValue(option string) string {
+	return strings.Trim(option[strings.Index(option, "=")+1:], `"`)
+}
+
+// parseSignerTime parses the time of a valid-after or valid-before option: YYYYMMDD[HHMM[SS]] in local time,
+// or in UTC with a trailing Z.
+func parseSignerTime(value string) (time.Time, error) {
+
+	location := time.Local
+	if strings.HasSuffix(value, "Z") || strings.HasSuffix(value, "z") {
+		value = value[:len(value)-1]
+		location = time.UTC
+	}
+
+	layouts := map[int]string{8: "20060102", 12: "200601021504", 14: "20060102150405"}
+	layout, ok := layouts[len(value)]
+	if !ok {
+		return time.Time{}, fmt.Errorf("invalid time %q, expected YYYYMMDD[HHMM[SS]][Z]", value)
+	}
+
+	t, err := time.ParseInLocation(layout, value, location)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q, expected YYYYMMDD[HHMM[SS]][Z]", value)
+	}
+
+	return t, nil
+}
+
+// unarmorSSH decodes the base64 blob between the BEGIN and END SSH SIGNATURE lines.
+func unarmorSSH(data []byte) ([]byte, error) {
+
+	text := strings.TrimSpace(string(data))
+
+	const begin, end = "-----BEGIN SSH SIGNATURE-----", "-----END SSH SIGNATURE-----"
+	if !strings.HasPrefix(text, begin) || !strings.HasSuffix(text, end) {
+		return nil, errors.New("missing SSH SIGNATURE armor")
+	}
+
+	encoded := strings.Join(strings.Fields(text[len(begin):len(text)-len(end)]), "")
+
+	return base64.StdEncoding.DecodeString(encoded)
+}
+
+// VerifyFile checks the signature against the file at path.
+func VerifyFile(verifier Verifier, path string, signature []byte) (string, error) {
+
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+
+	//noinspection GoUnhandledErrorResult
+	defer file.Close()
+
+	return verifier.Verify(file, signature)
+}
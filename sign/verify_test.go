@@ -0,0 +1,306 @@
+package sign
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha512"
+	"errors"
+	"golang.org/x/crypto/openpgp"
+	"golang.org/x/crypto/openpgp/armor"
+	"golang.org/x/crypto/ssh"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// sign signs the message with the signer and returns the signature.
+func sign(t *testing.T, signer Signer, message []byte) []byte {
+
+	var signature bytes.Buffer
+	if err := signer.Sign(bytes.NewReader(message), &signature); err != nil {
+		t.Fatal(err)
+	}
+
+	return signature.Bytes()
+}
+
+// writePGPKey writes the private key of the entity to dir, and its public key to dir with the suffix .pub.
+func writePGPKey(t *testing.T, dir string, name string, entity *openpgp.Entity) string {
+
+	path := filepath.Join(dir, name)
+	for _, k := range []struct {
+		path      string
+		blockType string
+		serialize func(*bytes.Buffer) error
+	}{
+		{path, openpgp.PrivateKeyType, func(b *bytes.Buffer) error { return entity.SerializePrivate(b, nil) }},
+		{path + ".pub", openpgp.PublicKeyType, func(b *bytes.Buffer) error { return entity.Serialize(b) }},
+	} {
+		var buffer bytes.Buffer
+		w, err := armor.Encode(&buffer, k.blockType, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var key bytes.Buffer
+		if err = k.serialize(&key); err != nil {
+			t.Fatal(err)
+		}
+		if _, err = w.Write(key.Bytes()); err != nil {
+			t.Fatal(err)
+		}
+		if err = w.Close(); err != nil {
+			t.Fatal(err)
+		}
+		if err = ioutil.WriteFile(k.path, buffer.Bytes(), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return path
+}
+
+func TestPGPVerifier(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "verify")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	trusted, err := openpgp.NewEntity("Release", "", "release@example.com", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := openpgp.NewEntity("Other", "", "other@example.com", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	trustedPath := writePGPKey(t, dir, "trusted", trusted)
+	otherPath := writePGPKey(t, dir, "other", other)
+
+	verifier, err := NewPGPVerifier(trustedPath + ".pub")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if verifier.Extension() != ".asc" {
+		t.Errorf("extension %q, want .asc", verifier.Extension())
+	}
+
+	message := []byte("the contents of an asset\n")
+
+	trustedSigner, err := NewPGPSigner(trustedPath, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherSigner, err := NewPGPSigner(otherPath, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		message   []byte
+		signature []byte
+		wantErr   bool
+	}{
+		{"trusted key", message, sign(t, trustedSigner, message), false},
+		{"wrong key", message, sign(t, otherSigner, message), true},
+		{"tampered message", []byte("the contents of another asset\n"), sign(t, trustedSigner, message), true},
+		{"garbage", message, []byte("not a signature"), true},
+	}
+
+	for _, test := range tests {
+		identity, err := verifier.Verify(bytes.NewReader(test.message), test.signature)
+		if test.wantErr {
+			if !errors.Is(err, ErrBadSignature) {
+				t.Errorf("%s: Verify = %q, %v, want ErrBadSignature", test.name, identity, err)
+			}
+			continue
+		}
+		if err != nil || identity != "Release <release@example.com>" {
+			t.Errorf("%s: Verify = %q, %v, want Release <release@example.com>", test.name, identity, err)
+		}
+	}
+}
+
+// sha1SSHSignature signs the message in the SSHSIG format with the legacy ssh-rsa SHA-1 algorithm.
+func sha1SSHSignature(t *testing.T, key *rsa.PrivateKey, namespace string, message []byte) []byte {
+
+	signer, err := ssh.NewSignerFromKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h := sha512.Sum512(message)
+	signedData := append([]byte(sshSigMagic), ssh.Marshal(struct {
+		Namespace     string
+		Reserved      string
+		HashAlgorithm string
+		Hash          []byte
+	}{namespace, "", "sha512", h[:]})...)
+
+	signature, err := signer.(ssh.AlgorithmSigner).SignWithAlgorithm(rand.Reader, signedData, ssh.SigAlgoRSA)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	blob := append([]byte(sshSigMagic), ssh.Marshal(struct {
+		Version       uint32
+		PublicKey     []byte
+		Namespace     string
+		Reserved      string
+		HashAlgorithm string
+		Signature     []byte
+	}{1, signer.PublicKey().Marshal(), namespace, "", "sha512", ssh.Marshal(signature)})...)
+
+	var armored bytes.Buffer
+	if err = armorSSH(&armored, blob); err != nil {
+		t.Fatal(err)
+	}
+
+	return armored.Bytes()
+}
+
+func TestSSHVerifier(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "verify")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	_, trustedKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, otherKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signer := func(name string, key interface{}, namespace string) Signer {
+		s, err := NewSSHSigner(writeKey(t, dir, name, key), nil, namespace)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+
+	trusted := signer("trusted", trustedKey, "")
+	other := signer("other", otherKey, "")
+	otherNamespace := signer("trusted-release", trustedKey, "release")
+	rsaSigner := signer("rsa", rsaKey, "")
+
+	authorizedKey := func(s Signer) string {
+		return strings.TrimSpace(string(ssh.MarshalAuthorizedKey(s.(*sshSigner).signer.PublicKey())))
+	}
+
+	message := []byte("the contents of an asset\n")
+	trustedLine := "release@example.com " + authorizedKey(trusted)
+	rsaLine := "release@example.com " + authorizedKey(rsaSigner)
+
+	tests := []struct {
+		name           string
+		allowedSigners string
+		message        []byte
+		signature      []byte
+		wantErr        bool
+	}{
+		{"trusted key", trustedLine, message, sign(t, trusted, message), false},
+		{"several principals", "ci@example.com,release@example.com " + authorizedKey(trusted), message,
+			sign(t, trusted, message), false},
+		{"wrong key", trustedLine, message, sign(t, other, message), true},
+		{"wrong namespace", trustedLine, message, sign(t, otherNamespace, message), true},
+		{"tampered message", trustedLine, []byte("the contents of another asset\n"), sign(t, trusted, message), true},
+		{"garbage", trustedLine, message, []byte("not a signature"), true},
+
+		{"namespace allowed", `release@example.com namespaces="git,file" ` + authorizedKey(trusted), message,
+			sign(t, trusted, message), false},
+		{"namespace restricted", `release@example.com namespaces="git" ` + authorizedKey(trusted), message,
+			sign(t, trusted, message), true},
+		{"certificate authority", "*@example.com cert-authority " + authorizedKey(trusted), message,
+			sign(t, trusted, message), true},
+
+		{"valid after", `release@example.com valid-after="20000101Z" ` + authorizedKey(trusted), message,
+			sign(t, trusted, message), false},
+		{"not yet valid", `release@example.com valid-after="29990101" ` + authorizedKey(trusted), message,
+			sign(t, trusted, message), true},
+		{"expired", `release@example.com valid-before="20000101" ` + authorizedKey(trusted), message,
+			sign(t, trusted, message), true},
+		{"valid before", `release@example.com valid-before="299901011200Z" ` + authorizedKey(trusted), message,
+			sign(t, trusted, message), false},
+
+		{"rsa-sha2-512", rsaLine, message, sign(t, rsaSigner, message), false},
+		{"ssh-rsa", rsaLine, message, sha1SSHSignature(t, rsaKey, DefaultNamespace, message), true},
+	}
+
+	for _, test := range tests {
+
+		path := filepath.Join(dir, "allowed_signers")
+		if err := ioutil.WriteFile(path, []byte("# allowed signers\n\n"+test.allowedSigners+"\n"), 0600); err != nil {
+			t.Fatal(err)
+		}
+
+		verifier, err := NewSSHVerifier(path, "")
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if verifier.Extension() != ".sig" {
+			t.Errorf("%s: extension %q, want .sig", test.name, verifier.Extension())
+		}
+
+		identity, err := verifier.Verify(bytes.NewReader(test.message), test.signature)
+		if test.wantErr {
+			if !errors.Is(err, ErrBadSignature) {
+				t.Errorf("%s: Verify = %q, %v, want ErrBadSignature", test.name, identity, err)
+			}
+			continue
+		}
+		if err != nil || !strings.Contains(identity, "release@example.com") {
+			t.Errorf("%s: Verify = %q, %v, want release@example.com", test.name, identity, err)
+		}
+	}
+}
+
+func TestNewSSHVerifierInvalid(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "verify")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := NewSSHSigner(writeKey(t, dir, "key", key), nil, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	authorizedKey := strings.TrimSpace(string(ssh.MarshalAuthorizedKey(signer.(*sshSigner).signer.PublicKey())))
+
+	for _, line := range []string{
+		"release@example.com",
+		"release@example.com ssh-ed25519 not-base64",
+		`release@example.com valid-before="2000" ` + authorizedKey,
+		`release@example.com valid-after="20001301" ` + authorizedKey,
+	} {
+		path := filepath.Join(dir, "allowed_signers")
+		if err := ioutil.WriteFile(path, []byte(line+"\n"), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := NewSSHVerifier(path, ""); err == nil {
+			t.Errorf("NewSSHVerifier accepted %q", line)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package utils
+
+import (
+	"fmt"
+	"github.com/fatih/color"
+	"io"
+	"os"
+)
+
+// DiffOp tells whether a line of a diff is kept, removed or added.
+type DiffOp byte
+
+const (
+	DiffKeep   DiffOp = ' '
+	DiffRemove DiffOp = '-'
+	DiffAdd    DiffOp = '+'
+)
+
+type DiffLine struct {
+	Op   DiffOp
+	Text string
+}
+
+// Diff returns the lines of a and b in order, marking those only in a as removed and those only in b
+// as added, along the longest common subsequence of both.
+func Diff(a []string, b []string) []DiffLine {
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			switch {
+			case a[i] == b[j]:
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				lcs[i][j] = lcs[i+1][j]
+			default:
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var lines []DiffLine
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			lines = append(lines, DiffLine{DiffKeep, a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			lines = append(lines, DiffLine{DiffRemove, a[i]})
+			i++
+		default:
+			lines = append(lines, DiffLine{DiffAdd, b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		lines = append(lines, DiffLine{DiffRemove, a[i]})
+	}
+	for ; j < len(b); j++ {
+		lines = append(lines, DiffLine{DiffAdd, b[j]})
+	}
+
+	return lines
+}
+
+// Changed reports whether the diff removes or adds any line.
+func Changed(lines []DiffLine) bool {
+
+	for _, line := range lines {
+		if line.Op != DiffKeep {
+			return true
+		}
+	}
+
+	return false
+}
+
+// PrintDiff writes the diff of a and b to stdout in the style of diff -u, with the whole text as context.
+func PrintDiff(fromName string, toName string, a []string, b []string) {
+	WriteDiff(os.Stdout, fromName, toName, a, b)
+}
+
+// PrintDiffContext writes the diff of a and b to stdout with the given number of unchanged lines around the
+// changes, and "@@" between the parts.
+func PrintDiffContext(fromName string, toName string, a []string, b []string, context int) {
+	WriteDiffContext(os.Stdout, fromName, toName, a, b, context)
+}
+
+func WriteDiff(w io.Writer, fromName string, toName string, a []string, b []string) {
+	WriteDiffContext(w, fromName, toName, a, b, -1)
+}
+
+// WriteDiffContext writes the diff with the given number of unchanged lines around the changes, all of them
+// when it is negative.
+func WriteDiffContext(w io.Writer, fromName string, toName string, a []string, b []string, context int) {
+
+	_, _ = fmt.Fprintln(w, color.New(color.Bold).Sprintf("--- %s\n+++ %s", fromName, toName))
+
+	lines := Diff(a, b)
+
+	// near[i] tells whether the line is within context lines of a change.
+	near := make([]bool, len(lines))
+	for i, line := range lines {
+		if context < 0 {
+			near[i] = true
+			continue
+		}
+		if line.Op == DiffKeep {
+			continue
+		}
+		for j := i - context; j <= i+context; j++ {
+			if j >= 0 && j < len(lines) {
+				near[j] = true
+			}
+		}
+	}
+
+	skipped := false
+	for i, line := range lines {
+		if !near[i] {
+			skipped = true
+			continue
+		}
+		if skipped {
+			_, _ = fmt.Fprintln(w, color.CyanString("@@"))
+			skipped = false
+		}
+
+		text := fmt.Sprintf("%c%s", line.Op, line.Text)
+		switch line.Op {
+		case DiffRemove:
+			text = color.RedString("%s", text)
+		case DiffAdd:
+			text = color.GreenString("%s", text)
+		}
+		_, _ = fmt.Fprintln(w, text)
+	}
+}
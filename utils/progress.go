@@ -0,0 +1,163 @@
+package utils
+
+import (
+	"fmt"
+	"github.com/mattn/go-isatty"
+	"github.com/spf13/viper"
+	"math"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	progressBarWidth   = 30
+	progressRedraw     = 100 * time.Millisecond
+	progressLogPeriod  = 10 * time.Second
+	progressNameLength = 32
+)
+
+// Progress reports the transfer of a single file: a bar redrawn on stderr when it is
+// a terminal, a log line every few seconds otherwise. Nothing is printed in essential mode.
+type Progress struct {
+	name     string
+	tty      bool
+	quiet    bool
+	finished bool
+
+	mu          sync.Mutex
+	start       time.Time
+	last        time.Time
+	transferred int64
+	total       int64
+}
+
+// NewProgress creates the reporter of a file. Pass bar as false when several transfers
+// run at the same time, their bars would overwrite each other.
+func NewProgress(name string, bar bool) *Progress {
+	return &Progress{
+		name:  name,
+		tty:   bar && (isatty.IsTerminal(os.Stderr.Fd()) || isatty.IsCygwinTerminal(os.Stderr.Fd())),
+		quiet: viper.GetBool("essential"),
+	}
+}
+
+// Update matches github.ProgressFunc.
+func (p *Progress) Update(transferred int64, total int64) {
+
+	if p.quiet {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+
+	// A transfer starting over from 0 is a retry, restart the clock.
+	if p.start.IsZero() || transferred < p.transferred {
+		p.start = now
+		p.last = time.Time{}
+		p.finished = false
+	}
+	if p.finished {
+		return
+	}
+	p.transferred, p.total = transferred, total
+
+	period := progressLogPeriod
+	if p.tty {
+		period = progressRedraw
+	}
+	if now.Sub(p.last) < period && transferred < total {
+		return
+	}
+	p.last = now
+
+	p.print(now)
+
+	if transferred >= total {
+		p.finish()
+	}
+}
+
+// Done terminates the bar line of a transfer that did not complete.
+func (p *Progress) Done() {
+
+	if p.quiet {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.start.IsZero() && !p.finished {
+		p.finish()
+	}
+}
+
+func (p *Progress) finish() {
+
+	p.finished = true
+
+	if p.tty {
+		_, _ = fmt.Fprintln(os.Stderr)
+	}
+}
+
+func (p *Progress) print(now time.Time) {
+
+	elapsed := now.Sub(p.start).Seconds()
+
+	var rate float64
+	if elapsed > 0 {
+		rate = float64(p.transferred) / elapsed
+	}
+
+	eta := "--"
+	if rate > 0 && p.transferred <= p.total {
+		eta = time.Duration(float64(p.total-p.transferred) / rate * float64(time.Second)).Round(time.Second).String()
+	}
+
+	// More than the total is transferred when the file grows, or the asset is replaced, on the way.
+	percent := 100.0
+	if p.total > 0 {
+		percent = math.Min(math.Max(float64(p.transferred)*100/float64(p.total), 0), 100)
+	}
+
+	stats := fmt.Sprintf("%5.1f%% %s/%s %s/s ETA %s",
+		percent, FormatBytes(p.transferred), FormatBytes(p.total), FormatBytes(int64(rate)), eta)
+
+	if !p.tty {
+		Info("%s: %s", p.name, stats)
+		return
+	}
+
+	filled := int(percent / 100 * progressBarWidth)
+	bar := strings.Repeat("=", filled) + strings.Repeat(" ", progressBarWidth-filled)
+
+	name := p.name
+	if len(name) > progressNameLength {
+		name = "..." + name[len(name)-progressNameLength+3:]
+	}
+
+	_, _ = fmt.Fprintf(os.Stderr, "\r%-*s [%s] %s\033[K", progressNameLength, name, bar, stats)
+}
+
+// FormatBytes formats a byte count with binary units, e.g. 1.5 MiB.
+func FormatBytes(n int64) string {
+
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}